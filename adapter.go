@@ -0,0 +1,60 @@
+package prowl4ai
+
+import (
+	"context"
+
+	"github.com/techbysteve/prowl4ai/internal/browser"
+	"github.com/techbysteve/prowl4ai/internal/config"
+)
+
+// FetchResult is the raw page content an Adapter returns for one URL. Set HTML
+// for HTML documents, or Body and ContentType for other responses.
+type FetchResult = browser.FetchResult
+
+// Adapter fetches pages for a Crawler. Implement it to plug in a custom
+// backend and pass it to NewCrawlerWithAdapter.
+type Adapter interface {
+	Start(ctx context.Context) error
+	FetchHTML(ctx context.Context, url string, cfg RunConfig) (FetchResult, error)
+	Close(ctx context.Context) error
+}
+
+// adapterBridge exposes a public Adapter as the internal browser.Adapter.
+type adapterBridge struct {
+	adapter Adapter
+}
+
+func (b adapterBridge) Start(ctx context.Context) error {
+	return b.adapter.Start(ctx)
+}
+
+func (b adapterBridge) FetchHTML(ctx context.Context, url string, cfg config.CrawlerRunConfig) (browser.FetchResult, error) {
+	return b.adapter.FetchHTML(ctx, url, fromInternalRunConfig(cfg))
+}
+
+func (b adapterBridge) Close(ctx context.Context) error {
+	return b.adapter.Close(ctx)
+}
+
+// NewAdapter returns the built-in adapter selected by cfg.FetchMode, so custom
+// adapters can wrap it, e.g. to record fixtures from live pages.
+func NewAdapter(cfg BrowserConfig) Adapter {
+	return builtinAdapter{adapter: browser.NewAdapter(toInternalBrowserConfig(cfg))}
+}
+
+// builtinAdapter exposes an internal browser.Adapter as a public Adapter.
+type builtinAdapter struct {
+	adapter browser.Adapter
+}
+
+func (b builtinAdapter) Start(ctx context.Context) error {
+	return b.adapter.Start(ctx)
+}
+
+func (b builtinAdapter) FetchHTML(ctx context.Context, url string, cfg RunConfig) (FetchResult, error) {
+	return b.adapter.FetchHTML(ctx, url, toInternalRunConfig(cfg))
+}
+
+func (b builtinAdapter) Close(ctx context.Context) error {
+	return b.adapter.Close(ctx)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/techbysteve/prowl4ai/internal/cache"
+)
+
+const cacheUsage = "usage: prowl4ai cache <stats|purge|export> [--cache-dir dir] [--older-than duration]"
+
+func runCache(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, cacheUsage)
+		return 2
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("cache", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	cacheDir := fs.String("cache-dir", "", "Cache directory (default: user cache directory)")
+	olderThan := fs.Duration("older-than", 0, "With purge, only remove entries fetched longer ago than this, e.g. 72h")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, cacheUsage)
+		return 2
+	}
+
+	store, err := cache.OpenDisk(*cacheDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open cache: %v\n", err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	switch action {
+	case "stats":
+		stats, err := store.Stats()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cache stats: %v\n", err)
+			return 1
+		}
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(struct {
+			Dir string `json:"dir"`
+			cache.Stats
+		}{store.Dir(), stats})
+	case "purge":
+		var cutoff time.Time
+		if *olderThan > 0 {
+			cutoff = time.Now().Add(-*olderThan)
+		}
+		removed, err := store.Purge(cutoff)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cache purge: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "removed %d entries\n", removed)
+	case "export":
+		// One JSON entry per line so exports can be streamed and appended.
+		if err := store.Walk(func(entry cache.Entry) error {
+			return enc.Encode(entry)
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "cache export: %v\n", err)
+			return 1
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown cache action: %s\n", action)
+		fmt.Fprintln(os.Stderr, cacheUsage)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/techbysteve/prowl4ai/internal/browser"
+	"github.com/techbysteve/prowl4ai/internal/cache"
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/deepcrawl"
+	"github.com/techbysteve/prowl4ai/internal/model"
+	"github.com/techbysteve/prowl4ai/internal/prowler"
+)
+
+const deepUsage = "usage: prowl4ai deep [--strategy bfs|dfs|best_first] [--max-depth n] [--max-pages n] [--scope same_domain|subdomain|any] [--timeout ms] [--headless bool] [--fetch-mode browser|http|auto] [--cache-mode mode] [--cache-dir dir] [--filters file] [--rejections file] [--scorers file] [--keywords list] [--ignore-robots] [--state file] [--checkpoint-interval duration] (<seed-url>... | --seeds file | --resume file)"
+
+// runDeep follows links from the seed URLs and prints one JSON result per
+// line as pages are crawled.
+func runDeep(args []string) int {
+	fs := flag.NewFlagSet("deep", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	defaults := config.DefaultDeepCrawlConfig()
+	strategy := fs.String("strategy", defaults.Strategy, "Crawl order: bfs|dfs|best_first")
+	maxDepth := fs.Int("max-depth", defaults.MaxDepth, "Maximum link hops from the seeds; negative for no limit")
+	maxPages := fs.Int("max-pages", defaults.MaxPages, "Maximum pages to crawl; 0 for no limit")
+	scope := fs.String("scope", defaults.Scope, "Link boundary: same_domain|subdomain|any")
+	timeoutMs := fs.Int("timeout", config.DefaultPageTimeoutMs, "Page timeout in milliseconds")
+	headless := fs.Bool("headless", true, "Run browser in headless mode")
+	fetchMode := fs.String("fetch-mode", config.DefaultFetchMode, "Fetch mode: browser|http|auto")
+	cacheMode := fs.String("cache-mode", config.DefaultCacheMode, "Cache mode: enabled|disabled|read_only|write_only|bypass")
+	cacheDir := fs.String("cache-dir", "", "Cache directory (default: user cache directory)")
+	filtersPath := fs.String("filters", "", "JSON file with an array of URL filter specs")
+	scorersPath := fs.String("scorers", "", "JSON file with an array of URL scorer specs for best_first")
+	keywords := fs.String("keywords", "", "Comma-separated keywords; best_first favors links mentioning them")
+	rejectionsPath := fs.String("rejections", "", "Write rejected links with their reasons to this file as JSON lines")
+	ignoreRobots := fs.Bool("ignore-robots", false, "Crawl pages robots.txt disallows and ignore its Crawl-delay")
+	statePath := fs.String("state", "", "Checkpoint the crawl state to this file")
+	checkpointInterval := fs.Duration("checkpoint-interval", 0, "Minimum time between checkpoints, e.g. 30s; 0 checkpoints after every page")
+	seedsPath := fs.String("seeds", "", "Read seed URLs from this file (- for stdin), one URL or JSON object with a url field per line")
+	resumePath := fs.String("resume", "", "Continue the crawl checkpointed in this file; strategy, limits, scope, filters, scorers and keywords come from the checkpoint")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	seeds := fs.Args()
+	if *seedsPath != "" {
+		fileSeeds, err := readSeeds(*seedsPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --seeds: %v\n", err)
+			return 2
+		}
+		seeds = append(seeds, fileSeeds...)
+	}
+	if (len(seeds) == 0) == (*resumePath == "") {
+		fmt.Fprintln(os.Stderr, deepUsage)
+		return 2
+	}
+	var state *deepcrawl.State
+	if *resumePath != "" {
+		var err error
+		if state, err = deepcrawl.LoadState(*resumePath); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --resume: %v\n", err)
+			return 2
+		}
+		if *statePath == "" {
+			*statePath = *resumePath
+		}
+	}
+
+	browserCfg := config.DefaultBrowserConfig()
+	browserCfg.Headless = *headless
+	browserCfg.FetchMode = *fetchMode
+
+	runCfg := config.DefaultCrawlerRunConfig()
+	runCfg.PageTimeoutMs = *timeoutMs
+	runCfg.CacheMode = *cacheMode
+
+	service := prowler.NewService(browser.NewAdapter(browserCfg))
+	if *cacheDir != "" {
+		store, err := cache.OpenDisk(*cacheDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "open cache: %v\n", err)
+			return 1
+		}
+		service.SetCache(store)
+	}
+
+	defer func() {
+		if err := service.Close(context.Background()); err != nil {
+			fmt.Fprintf(os.Stderr, "close error: %v\n", err)
+		}
+	}()
+	// Stop on SIGINT/SIGTERM; the crawler writes a final checkpoint first.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Filters and scorers go into the deep crawl config as specs so
+	// checkpoints carry them to --resume.
+	deepCfg := config.DeepCrawlConfig{
+		Strategy:        *strategy,
+		MaxDepth:        *maxDepth,
+		MaxPages:        *maxPages,
+		Scope:           *scope,
+		IgnoreRobotsTxt: *ignoreRobots,
+	}
+	if *filtersPath != "" {
+		specs, err := deepcrawl.ReadFilterSpecs(*filtersPath)
+		if err == nil {
+			_, err = deepcrawl.BuildFilters(specs)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --filters: %v\n", err)
+			return 2
+		}
+		deepCfg.Filters = specs
+	}
+	if *scorersPath != "" {
+		specs, err := deepcrawl.ReadScorerSpecs(*scorersPath)
+		if err == nil {
+			_, err = deepcrawl.BuildScorer(specs)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --scorers: %v\n", err)
+			return 2
+		}
+		deepCfg.Scorers = specs
+	}
+	if *keywords != "" {
+		// Keywords weigh as much as the --scorers specs together.
+		weight := 0.0
+		for _, spec := range deepCfg.Scorers {
+			weight += cmp.Or(spec.Weight, 1)
+		}
+		deepCfg.Scorers = append(deepCfg.Scorers, config.ScorerConfig{
+			Type:     config.ScorerKeyword,
+			Keywords: strings.Split(*keywords, ","),
+			Weight:   cmp.Or(weight, 1),
+		})
+	}
+
+	opts := deepcrawl.Options{StatePath: *statePath, CheckpointInterval: *checkpointInterval}
+	if *rejectionsPath != "" {
+		flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		if state != nil {
+			flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		}
+		f, err := os.OpenFile(*rejectionsPath, flags, 0o644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "open rejections file: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		rejections := json.NewEncoder(f)
+		opts.OnReject = func(r deepcrawl.Rejection) {
+			_ = rejections.Encode(r)
+		}
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	failed := 0
+	emit := func(result model.CrawlResult) error {
+		if !result.Success {
+			failed++
+		}
+		return enc.Encode(result)
+	}
+	crawler := deepcrawl.New(service, deepCfg, opts)
+	var err error
+	if state != nil {
+		err = crawler.Resume(ctx, state, runCfg, emit)
+	} else {
+		err = crawler.Run(ctx, seeds, runCfg, emit)
+	}
+	if err != nil && ctx.Err() != nil && *statePath != "" {
+		fmt.Fprintf(os.Stderr, "deep crawl interrupted; continue with --resume %s\n", *statePath)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "deep crawl: %v\n", err)
+		return 1
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "%d pages failed\n", failed)
+	}
+	return 0
+}
+
+// readSeeds reads seed URLs from path, or stdin for "-". Each line holds a
+// URL or a JSON object with a "url" field, as printed by the sitemap and
+// deep commands; blank lines and lines starting with # are skipped.
+func readSeeds(path string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	var seeds []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), 16<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "{") {
+			var entry struct {
+				URL string `json:"url"`
+			}
+			if err := json.Unmarshal([]byte(line), &entry); err != nil || entry.URL == "" {
+				return nil, fmt.Errorf("invalid seed line: %s", line)
+			}
+			line = entry.URL
+		}
+		seeds = append(seeds, line)
+	}
+	return seeds, scanner.Err()
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/techbysteve/prowl4ai/internal/browser"
+	"github.com/techbysteve/prowl4ai/internal/cache"
+	"github.com/techbysteve/prowl4ai/internal/change"
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/model"
+	"github.com/techbysteve/prowl4ai/internal/prowler"
+	"github.com/techbysteve/prowl4ai/internal/stderrors"
+)
+
+const diffUsage = "usage: prowl4ai diff [--output text|json] [--min-similarity n] (<old.json> <new.json> | --url url [--fetch-mode mode] [--timeout ms] [--cache-dir dir])"
+
+// runDiff compares two saved crawl results, or the cached version of a URL
+// with a fresh crawl. Like diff(1) it exits 0 when the content is unchanged,
+// 1 when it changed and 2 on errors.
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	output := fs.String("output", "text", "Output format: text|json")
+	minSimilarity := fs.Float64("min-similarity", 1, "Report a change only when similarity falls below this value (0-1); 1 reports every change")
+	url := fs.String("url", "", "Compare the cached version of this URL with a fresh crawl")
+	fetchMode := fs.String("fetch-mode", config.DefaultFetchMode, "Fetch mode for --url: browser|http|auto")
+	timeoutMs := fs.Int("timeout", config.DefaultPageTimeoutMs, "Page timeout in milliseconds for --url")
+	cacheDir := fs.String("cache-dir", "", "Cache directory (default: user cache directory)")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintln(os.Stderr, "invalid --output value, expected: text|json")
+		return 2
+	}
+
+	var report change.Report
+	switch {
+	case *url != "" && fs.NArg() == 0:
+		r, err := diffWithCache(*url, *fetchMode, *timeoutMs, *cacheDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+			return 2
+		}
+		report = r
+	case *url == "" && fs.NArg() == 2:
+		old, err := readResult(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+			return 2
+		}
+		fresh, err := readResult(fs.Arg(1))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+			return 2
+		}
+		report = change.Diff(old, fresh)
+	default:
+		fmt.Fprintln(os.Stderr, diffUsage)
+		return 2
+	}
+
+	if *output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to encode report: %v\n", err)
+			return 2
+		}
+	} else {
+		fmt.Print(report.Diff)
+		fmt.Printf("similarity: %.4f (simhash distance %d)\n", report.Similarity, report.SimHashDistance)
+	}
+
+	if report.Changed && (*minSimilarity >= 1 || report.Similarity < *minSimilarity) {
+		return 1
+	}
+	return 0
+}
+
+func diffWithCache(url, fetchMode string, timeoutMs int, cacheDir string) (change.Report, error) {
+	browserCfg := config.DefaultBrowserConfig()
+	browserCfg.FetchMode = fetchMode
+
+	service := prowler.NewService(browser.NewAdapter(browserCfg))
+	store, err := cache.OpenDisk(cacheDir)
+	if err != nil {
+		return change.Report{}, err
+	}
+	service.SetCache(store)
+
+	ctx := context.Background()
+	defer func() {
+		if err := service.Close(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "close error: %v\n", err)
+		}
+	}()
+
+	runCfg := config.DefaultCrawlerRunConfig()
+	runCfg.PageTimeoutMs = timeoutMs
+	report, _, err := service.CompareWithCache(ctx, url, runCfg)
+	if errors.Is(err, stderrors.ErrNotCached) {
+		return change.Report{}, fmt.Errorf("%w for %s; crawl it first with --cache-mode enabled", err, url)
+	}
+	return report, err
+}
+
+func readResult(path string) (model.CrawlResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return model.CrawlResult{}, err
+	}
+	var result model.CrawlResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return model.CrawlResult{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return result, nil
+}
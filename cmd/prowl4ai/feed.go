@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/techbysteve/prowl4ai/internal/browser"
+	"github.com/techbysteve/prowl4ai/internal/cache"
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/feed"
+	"github.com/techbysteve/prowl4ai/internal/prowler"
+)
+
+const feedUsage = "usage: prowl4ai feed [--watermarks file] [--feeds file] [--timeout ms] [--headless bool] [--fetch-mode browser|http|auto] [--cache-mode mode] [--cache-dir dir] [--check-robots] <feed-or-page-url>..."
+
+// runFeed polls feeds and prints one JSON line per new item with the item
+// and the crawl of its page.
+func runFeed(args []string) int {
+	fs := flag.NewFlagSet("feed", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	watermarksPath := fs.String("watermarks", "", "Watermark file recording the items already crawled (default: feeds/watermarks.json in the user cache directory)")
+	feedsPath := fs.String("feeds", "", "Read feed URLs from this file (- for stdin), one per line")
+	timeoutMs := fs.Int("timeout", config.DefaultPageTimeoutMs, "Page timeout in milliseconds")
+	headless := fs.Bool("headless", true, "Run browser in headless mode")
+	fetchMode := fs.String("fetch-mode", config.DefaultFetchMode, "Fetch mode: browser|http|auto")
+	cacheMode := fs.String("cache-mode", config.DefaultCacheMode, "Cache mode: enabled|disabled|read_only|write_only|bypass")
+	cacheDir := fs.String("cache-dir", "", "Cache directory (default: user cache directory)")
+	checkRobots := fs.Bool("check-robots", false, "Refuse item URLs disallowed by robots.txt and honor its Crawl-delay")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	feedURLs := fs.Args()
+	if *feedsPath != "" {
+		fileURLs, err := readSeeds(*feedsPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --feeds: %v\n", err)
+			return 2
+		}
+		feedURLs = append(feedURLs, fileURLs...)
+	}
+	if len(feedURLs) == 0 {
+		fmt.Fprintln(os.Stderr, feedUsage)
+		return 2
+	}
+
+	store, err := feed.OpenWatermarks(*watermarksPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open watermarks: %v\n", err)
+		return 1
+	}
+
+	browserCfg := config.DefaultBrowserConfig()
+	browserCfg.Headless = *headless
+	browserCfg.FetchMode = *fetchMode
+
+	runCfg := config.DefaultCrawlerRunConfig()
+	runCfg.PageTimeoutMs = *timeoutMs
+	runCfg.CacheMode = *cacheMode
+	runCfg.CheckRobotsTxt = *checkRobots
+
+	service := prowler.NewService(browser.NewAdapter(browserCfg))
+	if *cacheDir != "" {
+		cacheStore, err := cache.OpenDisk(*cacheDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "open cache: %v\n", err)
+			return 1
+		}
+		service.SetCache(cacheStore)
+	}
+	defer func() {
+		if err := service.Close(context.Background()); err != nil {
+			fmt.Fprintf(os.Stderr, "close error: %v\n", err)
+		}
+	}()
+	// Stop on SIGINT/SIGTERM; the current feed's watermark is saved first.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	poller := feed.NewPoller(feed.NewClient(browserCfg.UserAgent), service, store)
+	enc := json.NewEncoder(os.Stdout)
+	status := 0
+	for _, feedURL := range feedURLs {
+		n, err := poller.Poll(ctx, feedURL, runCfg, func(r feed.ItemResult) error {
+			return enc.Encode(r)
+		})
+		if ctx.Err() != nil {
+			fmt.Fprintln(os.Stderr, "feed polling interrupted")
+			return 1
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "feed %s: %v\n", feedURL, err)
+			status = 1
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "feed %s: %d new items\n", feedURL, n)
+	}
+	return status
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/sitemap"
+)
+
+const sitemapUsage = "usage: prowl4ai sitemap [--output urls|json] [--since time] [--until time] [--max-sitemaps n] <site|sitemap-url>"
+
+// runSitemap lists the pages in a site's sitemaps, one per line, so the
+// output can seed a deep crawl.
+func runSitemap(args []string) int {
+	fs := flag.NewFlagSet("sitemap", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	output := fs.String("output", "urls", "Output format: urls (one per line) or json (one entry per line)")
+	since := fs.String("since", "", "Only entries modified at or after this date, RFC 3339 time or age such as 72h")
+	until := fs.String("until", "", "Only entries modified at or before this date or RFC 3339 time")
+	maxSitemaps := fs.Int("max-sitemaps", sitemap.DefaultMaxSitemaps, "Maximum sitemaps to fetch, indexes included")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, sitemapUsage)
+		return 2
+	}
+	if *output != "urls" && *output != "json" {
+		fmt.Fprintln(os.Stderr, "invalid --output value, expected: urls|json")
+		return 2
+	}
+	opts := sitemap.Options{MaxSitemaps: *maxSitemaps}
+	var err error
+	if opts.Since, err = parseTimeFlag(*since, true); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --since value: %v\n", err)
+		return 2
+	}
+	if opts.Until, err = parseTimeFlag(*until, false); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --until value: %v\n", err)
+		return 2
+	}
+
+	result, err := sitemap.NewClient(config.DefaultUserAgent).Discover(context.Background(), fs.Arg(0), opts)
+	for _, msg := range result.Errors {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", msg)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sitemap: %v\n", err)
+		return 1
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	enc := json.NewEncoder(out)
+	for _, e := range result.Entries {
+		if *output == "json" {
+			_ = enc.Encode(e)
+		} else {
+			fmt.Fprintln(out, e.URL)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d urls from %d sitemaps\n", len(result.Entries), len(result.Sitemaps))
+	return 0
+}
+
+// parseTimeFlag parses a date, an RFC 3339 time or, when ageAllowed, a
+// duration counted back from now. An empty value is the zero time.
+func parseTimeFlag(value string, ageAllowed bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if ageAllowed {
+		if age, err := time.ParseDuration(value); err == nil {
+			return time.Now().Add(-age), nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
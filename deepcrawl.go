@@ -0,0 +1,194 @@
+package prowl4ai
+
+import (
+	"context"
+	"time"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/deepcrawl"
+	"github.com/techbysteve/prowl4ai/internal/model"
+)
+
+// URLCandidate is a discovered link considered for a deep crawl's frontier.
+type URLCandidate = deepcrawl.Candidate
+
+// URLScorer rates links for best-first deep crawls; higher scores are
+// crawled first.
+type URLScorer = deepcrawl.URLScorer
+
+// URLScorerSpec declares a built-in scorer and its weight, e.g.
+// {"type": "keyword", "keywords": ["api", "guide"], "weight": 2}. Types are
+// "keyword", "path_depth", "freshness", "domain_authority" and "content_type".
+type URLScorerSpec = config.ScorerConfig
+
+// Built-in URL scorers. Scores range from 0 to 1; CompositeScorer averages
+// its parts by weight.
+type (
+	KeywordScorer         = deepcrawl.KeywordScorer
+	PathDepthScorer       = deepcrawl.PathDepthScorer
+	FreshnessScorer       = deepcrawl.FreshnessScorer
+	DomainAuthorityScorer = deepcrawl.DomainAuthorityScorer
+	ContentTypeScorer     = deepcrawl.ContentTypeScorer
+	WeightedScorer        = deepcrawl.WeightedScorer
+	CompositeScorer       = deepcrawl.CompositeScorer
+)
+
+// LoadURLScorer reads a JSON array of URLScorerSpec from path and combines
+// them into one scorer.
+func LoadURLScorer(path string) (URLScorer, error) {
+	return deepcrawl.LoadScorer(path)
+}
+
+// Link is a hyperlink found on a crawled page.
+type Link = model.Link
+
+// URLFilter decides whether a discovered link may enter a deep crawl's
+// frontier. Check returns nil to accept it or an error giving the reason.
+type URLFilter = deepcrawl.URLFilter
+
+// URLRejection records a link kept out of the frontier and why.
+type URLRejection = deepcrawl.Rejection
+
+// URLFilterSpec declares a built-in filter, e.g. in a JSON config file:
+// {"type": "domain", "deny": ["ads.example.com"]}. Types are "domain",
+// "glob", "regex", "path_prefix", "extension", "content_type" and
+// "max_query_params".
+type URLFilterSpec = config.FilterConfig
+
+// Built-in URL filters.
+type (
+	DomainFilter         = deepcrawl.DomainFilter
+	PatternFilter        = deepcrawl.PatternFilter
+	PathPrefixFilter     = deepcrawl.PathPrefixFilter
+	ExtensionFilter      = deepcrawl.ExtensionFilter
+	ContentTypeFilter    = deepcrawl.ContentTypeFilter
+	MaxQueryParamsFilter = deepcrawl.MaxQueryParamsFilter
+)
+
+// NewGlobFilter matches whole URLs against glob patterns, where "*" matches
+// any run of characters.
+func NewGlobFilter(include, exclude []string) PatternFilter {
+	return deepcrawl.NewGlobFilter(include, exclude)
+}
+
+// NewRegexFilter matches URLs against regular expressions.
+func NewRegexFilter(include, exclude []string) (PatternFilter, error) {
+	return deepcrawl.NewRegexFilter(include, exclude)
+}
+
+// LoadURLFilters reads a JSON array of URLFilterSpec from path.
+func LoadURLFilters(path string) ([]URLFilter, error) {
+	return deepcrawl.LoadFilters(path)
+}
+
+// DeepCrawlConfig controls link-following crawls from seed URLs.
+type DeepCrawlConfig struct {
+	// Strategy is "bfs" (default), "dfs" or "best_first".
+	Strategy string
+	// MaxDepth limits link hops from the seeds (negative for no limit) and
+	// MaxPages caps the pages crawled (non-positive for no cap).
+	MaxDepth int
+	MaxPages int
+	// Scope is "same_domain" (default), "subdomain" or "any", relative to the
+	// seed each link descends from.
+	Scope string
+	// ScorerSpecs declare built-in scorers for best-first crawls; Scorer is
+	// added to them at weight 1. With neither, shallow paths go first.
+	ScorerSpecs []URLScorerSpec
+	Scorer      URLScorer
+	// FilterSpecs declare built-in filters; Filters run after them. Both are
+	// applied to discovered links before they enter the frontier.
+	FilterSpecs []URLFilterSpec
+	Filters     []URLFilter
+	// OnResult, when set, receives each result as soon as it is crawled.
+	OnResult func(CrawlResult)
+	// OnReject, when set, receives every link kept out of the frontier.
+	OnReject func(URLRejection)
+	// IgnoreRobotsTxt turns off the robots.txt checks deep crawls make by
+	// default; see RunConfig.CheckRobotsTxt.
+	IgnoreRobotsTxt bool
+	// StatePath, when set, is where the crawl state is checkpointed so an
+	// interrupted crawl can continue with ResumeDeepCrawl. A checkpoint is
+	// written at least every CheckpointInterval (after every page when zero)
+	// and whenever the crawl stops, including on context cancellation.
+	StatePath          string
+	CheckpointInterval time.Duration
+}
+
+// DeepCrawlState is a deep crawl checkpoint as saved to DeepCrawlConfig.StatePath.
+type DeepCrawlState = deepcrawl.State
+
+// LoadDeepCrawlState reads a checkpoint saved by a deep crawl.
+func LoadDeepCrawlState(path string) (*DeepCrawlState, error) {
+	return deepcrawl.LoadState(path)
+}
+
+// DefaultDeepCrawlConfig returns breadth-first, same-domain crawl defaults.
+func DefaultDeepCrawlConfig() DeepCrawlConfig {
+	cfg := config.DefaultDeepCrawlConfig()
+	return DeepCrawlConfig{
+		Strategy: cfg.Strategy,
+		MaxDepth: cfg.MaxDepth,
+		MaxPages: cfg.MaxPages,
+		Scope:    cfg.Scope,
+	}
+}
+
+// DeepCrawl crawls seeds with the crawler's default run config and follows
+// the links it finds. Results come back in crawl order and record their
+// depth and parent URL. Failed pages are included with their error.
+func (c *Crawler) DeepCrawl(ctx context.Context, seeds []string, cfg DeepCrawlConfig) ([]CrawlResult, error) {
+	var results []CrawlResult
+	err := c.deepCrawler(cfg).Run(ctx, seeds, c.defaultRunConfig, collectResults(&results, cfg.OnResult))
+	return results, err
+}
+
+// ResumeDeepCrawl continues the deep crawl checkpointed at statePath. The
+// strategy, limits, scope and declared filters and scorers come from the
+// checkpoint; cfg supplies the hooks and checkpoint settings, and
+// checkpoints go back to statePath unless cfg.StatePath is set. Only pages
+// crawled after the resume are returned.
+func (c *Crawler) ResumeDeepCrawl(ctx context.Context, statePath string, cfg DeepCrawlConfig) ([]CrawlResult, error) {
+	state, err := deepcrawl.LoadState(statePath)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.StatePath == "" {
+		cfg.StatePath = statePath
+	}
+	var results []CrawlResult
+	err = c.deepCrawler(cfg).Resume(ctx, state, c.defaultRunConfig, collectResults(&results, cfg.OnResult))
+	return results, err
+}
+
+func (c *Crawler) deepCrawler(cfg DeepCrawlConfig) *deepcrawl.Crawler {
+	return deepcrawl.New(c.service, toInternalDeepCrawlConfig(cfg), deepcrawl.Options{
+		Scorer:             cfg.Scorer,
+		Filters:            cfg.Filters,
+		OnReject:           cfg.OnReject,
+		StatePath:          cfg.StatePath,
+		CheckpointInterval: cfg.CheckpointInterval,
+	})
+}
+
+func collectResults(results *[]CrawlResult, onResult func(CrawlResult)) func(model.CrawlResult) error {
+	return func(result model.CrawlResult) error {
+		*results = append(*results, result)
+		if onResult != nil {
+			onResult(result)
+		}
+		return nil
+	}
+}
+
+func toInternalDeepCrawlConfig(cfg DeepCrawlConfig) config.DeepCrawlConfig {
+	return config.DeepCrawlConfig{
+		Strategy:        cfg.Strategy,
+		MaxDepth:        cfg.MaxDepth,
+		MaxPages:        cfg.MaxPages,
+		Scope:           cfg.Scope,
+		Filters:         append([]config.FilterConfig{}, cfg.FilterSpecs...),
+		Scorers:         append([]config.ScorerConfig{}, cfg.ScorerSpecs...),
+		IgnoreRobotsTxt: cfg.IgnoreRobotsTxt,
+	}
+}
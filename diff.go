@@ -0,0 +1,28 @@
+package prowl4ai
+
+import (
+	"context"
+
+	"github.com/techbysteve/prowl4ai/internal/change"
+	"github.com/techbysteve/prowl4ai/internal/stderrors"
+)
+
+// DiffReport describes how a page's Markdown changed between two crawls: a
+// unified diff, a line similarity score and the SimHash bit distance.
+type DiffReport = change.Report
+
+// ErrNotCached is returned by DiffWithCache when the URL has no cached version.
+var ErrNotCached = stderrors.ErrNotCached
+
+// Diff compares the Markdown of two crawl results. Whitespace-only changes
+// are ignored; use the similarity score to skip small edits such as
+// refreshed timestamps.
+func Diff(old, new CrawlResult) DiffReport {
+	return change.Diff(old, new)
+}
+
+// DiffWithCache crawls url fresh, replaces its cache entry and compares the
+// result with the previously cached version.
+func (c *Crawler) DiffWithCache(ctx context.Context, url string, cfg RunConfig) (DiffReport, CrawlResult, error) {
+	return c.service.CompareWithCache(ctx, url, toInternalRunConfig(cfg))
+}
@@ -0,0 +1,55 @@
+package prowl4ai
+
+import (
+	"context"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/extract"
+	"github.com/techbysteve/prowl4ai/internal/feed"
+)
+
+// Feed is a parsed RSS 2.0, RSS 1.0, Atom or JSON feed.
+type Feed = feed.Feed
+
+// FeedItem is one feed entry.
+type FeedItem = feed.Item
+
+// FeedLink is a feed a page advertises; crawl results list them in
+// Metadata["feeds"].
+type FeedLink = extract.FeedLink
+
+// FeedItemResult pairs a new feed item with the crawl of its page.
+type FeedItemResult = feed.ItemResult
+
+// FeedWatermarks records, per feed, the items already crawled.
+type FeedWatermarks = feed.WatermarkStore
+
+// ParseFeed parses a feed document fetched from feedURL.
+func ParseFeed(data []byte, feedURL string) (Feed, error) {
+	return feed.Parse(data, feedURL)
+}
+
+// FetchFeed fetches and parses the feed at url. When url is an HTML page,
+// the first feed it advertises is fetched instead.
+func FetchFeed(ctx context.Context, url string) (Feed, error) {
+	return feed.NewClient(config.DefaultUserAgent).Fetch(ctx, url)
+}
+
+// OpenFeedWatermarks loads the watermark file at path, or the default file
+// in the user cache directory when path is empty.
+func OpenFeedWatermarks(path string) (*FeedWatermarks, error) {
+	return feed.OpenWatermarks(path)
+}
+
+// PollFeed crawls the items feedURL gained since its watermark, oldest
+// first, with the crawler's default run config, then advances and saves the
+// watermark. Items whose crawl fails are retried on the next poll.
+func (c *Crawler) PollFeed(ctx context.Context, feedURL string, marks *FeedWatermarks) ([]FeedItemResult, error) {
+	poller := feed.NewPoller(feed.NewClient(config.DefaultUserAgent), c.service, marks)
+	var results []FeedItemResult
+	_, err := poller.Poll(ctx, feedURL, c.defaultRunConfig, func(r FeedItemResult) error {
+		results = append(results, r)
+		return nil
+	})
+	return results, err
+}
@@ -2,8 +2,11 @@ package browser
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/model"
 )
 
 type FetchResult struct {
@@ -11,6 +14,17 @@ type FetchResult struct {
 	StatusCode      int
 	RedirectedURL   string
 	ResponseHeaders map[string][]string
+	// ContentType is the response Content-Type header value, if any.
+	ContentType string
+	// Body holds the raw response bytes for non-HTML responses such as PDF,
+	// JSON or plain text, where the rendered DOM is a viewer wrapper.
+	Body []byte
+	// Proxy is the proxy server that served the request, if any.
+	Proxy string
+	// UserAgent is the user agent the request was sent with, if known.
+	UserAgent string
+	// Downloads lists files the crawl saved when downloads are accepted.
+	Downloads []model.Download
 }
 
 type Adapter interface {
@@ -18,3 +32,60 @@ type Adapter interface {
 	FetchHTML(ctx context.Context, url string, cfg config.CrawlerRunConfig) (FetchResult, error)
 	Close(ctx context.Context) error
 }
+
+// FetchProfiler is implemented by adapters whose own settings change what a
+// fetch returns. The cache keys entries by the profile, so crawlers with a
+// different fetch mode or user agent do not serve each other's responses.
+type FetchProfiler interface {
+	FetchProfile() string
+}
+
+// fetchProfile describes the adapter settings that change responses: the
+// fetch mode, browser, user agent and the headers sent with every request.
+func fetchProfile(mode string, cfg config.BrowserConfig) string {
+	data, err := json.Marshal(struct {
+		Mode          string            `json:"mode"`
+		BrowserType   string            `json:"browser_type,omitempty"`
+		UserAgent     string            `json:"user_agent,omitempty"`
+		UserAgentMode string            `json:"user_agent_mode,omitempty"`
+		JavaScript    bool              `json:"javascript"`
+		Headers       map[string]string `json:"headers,omitempty"`
+	}{mode, cfg.BrowserType, cfg.UserAgent, cfg.UserAgentMode, cfg.JavaScriptEnabled, cfg.Headers})
+	if err != nil {
+		return mode
+	}
+	return string(data)
+}
+
+// NewAdapter returns the adapter selected by cfg.FetchMode.
+func NewAdapter(cfg config.BrowserConfig) Adapter {
+	switch cfg.FetchMode {
+	case "", config.FetchModeBrowser:
+		return NewPlaywrightAdapter(cfg)
+	case config.FetchModeHTTP:
+		return NewHTTPAdapter(cfg)
+	case config.FetchModeAuto:
+		return NewAutoAdapter(cfg)
+	default:
+		return &invalidAdapter{err: fmt.Errorf("unsupported fetch mode: %s", cfg.FetchMode)}
+	}
+}
+
+// invalidAdapter reports a configuration error from Start so that adapter
+// construction stays error-free, mirroring PlaywrightAdapter's handling of an
+// unknown browser type.
+type invalidAdapter struct {
+	err error
+}
+
+func (a *invalidAdapter) Start(ctx context.Context) error {
+	return a.err
+}
+
+func (a *invalidAdapter) FetchHTML(ctx context.Context, url string, cfg config.CrawlerRunConfig) (FetchResult, error) {
+	return FetchResult{}, a.err
+}
+
+func (a *invalidAdapter) Close(ctx context.Context) error {
+	return nil
+}
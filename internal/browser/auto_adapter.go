@@ -0,0 +1,150 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"golang.org/x/net/html"
+)
+
+// minRenderedTextLen is the visible text length below which a page that ships
+// scripts is assumed to render its content client-side.
+const minRenderedTextLen = 200
+
+// AutoAdapter fetches with HTTPAdapter first and falls back to
+// PlaywrightAdapter when the response looks JavaScript-rendered. The browser
+// is only launched on the first fallback.
+type AutoAdapter struct {
+	http    *HTTPAdapter
+	browser *PlaywrightAdapter
+}
+
+func NewAutoAdapter(cfg config.BrowserConfig) *AutoAdapter {
+	return &AutoAdapter{
+		http:    NewHTTPAdapter(cfg),
+		browser: NewPlaywrightAdapter(cfg),
+	}
+}
+
+func (a *AutoAdapter) Start(ctx context.Context) error {
+	return a.http.Start(ctx)
+}
+
+func (a *AutoAdapter) FetchHTML(ctx context.Context, url string, cfg config.CrawlerRunConfig) (FetchResult, error) {
+	result, err := a.http.FetchHTML(ctx, url, cfg)
+	if err == nil && !needsBrowser(result, cfg) {
+		return result, nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return FetchResult{}, ctxErr
+	}
+
+	if err := a.browser.Start(ctx); err != nil {
+		return FetchResult{}, err
+	}
+	return a.browser.FetchHTML(ctx, url, cfg)
+}
+
+// FetchProfile identifies the settings that shape this adapter's responses.
+func (a *AutoAdapter) FetchProfile() string {
+	return fetchProfile(config.FetchModeAuto, a.http.cfg)
+}
+
+// PoolStats reports the fallback browser's page pool usage.
+func (a *AutoAdapter) PoolStats() PoolStats {
+	return a.browser.PoolStats()
+}
+
+func (a *AutoAdapter) Close(ctx context.Context) error {
+	return errors.Join(a.http.Close(ctx), a.browser.Close(ctx))
+}
+
+// needsBrowser reports whether an HTTP-only result should be refetched with a
+// browser: bot challenges, browser-only run options, selectors missing from
+// the static HTML, or pages whose visible content is built by scripts.
+func needsBrowser(result FetchResult, cfg config.CrawlerRunConfig) bool {
+	switch result.StatusCode {
+	case 403, 429, 503:
+		return true
+	}
+	if cfg.DownloadSelector != "" || cfg.Device != "" || cfg.Geolocation != nil {
+		return true
+	}
+	if result.HTML == "" {
+		return false
+	}
+
+	doc, err := html.Parse(strings.NewReader(result.HTML))
+	if err != nil {
+		return true
+	}
+	if cfg.WaitFor != "" {
+		sel, err := cascadia.Compile(cfg.WaitFor)
+		if err != nil || cascadia.Query(doc, sel) == nil {
+			return true
+		}
+	}
+	return looksJSRendered(result.HTML)
+}
+
+// looksJSRendered is a cheap heuristic for client-rendered pages: almost no
+// visible text alongside scripts, or a <noscript> asking for JavaScript.
+func looksJSRendered(rawHTML string) bool {
+	tokenizer := html.NewTokenizer(strings.NewReader(rawHTML))
+	var (
+		textLen     int
+		scripts     int
+		skipDepth   int
+		inNoscript  bool
+		noscriptMsg bool
+	)
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if tokenizer.Err() != io.EOF {
+				return true
+			}
+			if noscriptMsg && textLen < 5*minRenderedTextLen {
+				return true
+			}
+			return scripts > 0 && textLen < minRenderedTextLen
+		case html.StartTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "script":
+				scripts++
+				skipDepth++
+			case "style", "template":
+				skipDepth++
+			case "noscript":
+				inNoscript = true
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "script", "style", "template":
+				if skipDepth > 0 {
+					skipDepth--
+				}
+			case "noscript":
+				inNoscript = false
+			}
+		case html.TextToken:
+			if skipDepth > 0 {
+				continue
+			}
+			text := strings.TrimSpace(string(tokenizer.Text()))
+			if inNoscript {
+				if strings.Contains(strings.ToLower(text), "javascript") {
+					noscriptMsg = true
+				}
+				continue
+			}
+			textLen += len(text)
+		}
+	}
+}
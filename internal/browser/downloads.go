@@ -0,0 +1,228 @@
+package browser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/techbysteve/prowl4ai/internal/model"
+)
+
+// maxPendingDownloads bounds how many downloads one page may queue before
+// further ones are dropped.
+const maxPendingDownloads = 32
+
+var errDownloadTooLarge = errors.New("download exceeds size cap")
+
+// downloadCollector buffers the downloads a page emits until the crawl saves
+// them. With a size cap it records the responses whose Content-Length
+// exceeds it, and cancels downloads of those as soon as they start.
+type downloadCollector struct {
+	ch       chan playwright.Download
+	maxBytes int64
+	mu       sync.Mutex
+	oversize map[string]int64
+}
+
+func newDownloadCollector(page playwright.Page, maxBytes int64) *downloadCollector {
+	c := &downloadCollector{
+		ch:       make(chan playwright.Download, maxPendingDownloads),
+		maxBytes: maxBytes,
+		oversize: map[string]int64{},
+	}
+	if maxBytes > 0 {
+		page.OnResponse(func(resp playwright.Response) {
+			n, err := strconv.ParseInt(resp.Headers()["content-length"], 10, 64)
+			if err == nil && n > maxBytes {
+				c.mu.Lock()
+				c.oversize[resp.URL()] = n
+				c.mu.Unlock()
+			}
+		})
+	}
+	// Handlers run on Playwright's connection loop, so round trips such as
+	// Cancel go to their own goroutine.
+	page.OnDownload(func(d playwright.Download) {
+		if _, tooLarge := c.declaredSize(d.URL()); tooLarge {
+			go d.Cancel()
+		}
+		select {
+		case c.ch <- d:
+		default:
+			go d.Cancel()
+		}
+	})
+	return c
+}
+
+// declaredSize returns the Content-Length of url's response when it exceeds
+// the size cap.
+func (c *downloadCollector) declaredSize(url string) (int64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n, ok := c.oversize[url]
+	return n, ok
+}
+
+// wait blocks until a download arrives or timeout elapses.
+func (c *downloadCollector) wait(timeout time.Duration) (playwright.Download, bool) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case d := <-c.ch:
+		return d, true
+	case <-timer.C:
+		return nil, false
+	}
+}
+
+// drain returns every download queued so far without blocking.
+func (c *downloadCollector) drain() []playwright.Download {
+	var out []playwright.Download
+	for {
+		select {
+		case d := <-c.ch:
+			out = append(out, d)
+		default:
+			return out
+		}
+	}
+}
+
+func (a *PlaywrightAdapter) downloadsDir() string {
+	if a.cfg.DownloadsPath != "" {
+		return a.cfg.DownloadsPath
+	}
+	return filepath.Join(os.TempDir(), "prowl4ai-downloads")
+}
+
+// saveDownload waits for d to finish, moves it into the downloads directory and
+// records its size, MIME type and SHA-256. Failures are reported on the entry.
+// Downloads announced larger than MaxDownloadBytes are cancelled unsaved;
+// ones without a Content-Length are checked once saved.
+func (a *PlaywrightAdapter) saveDownload(c *downloadCollector, d playwright.Download) model.Download {
+	entry := model.Download{
+		URL:               d.URL(),
+		SuggestedFilename: d.SuggestedFilename(),
+	}
+
+	if size, tooLarge := c.declaredSize(d.URL()); tooLarge {
+		_ = d.Cancel()
+		_ = d.Delete()
+		entry.Error = fmt.Sprintf("%s: %d > %d bytes", errDownloadTooLarge, size, c.maxBytes)
+		return entry
+	}
+	if err := d.Failure(); err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+
+	dir := a.downloadsDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	path, err := uniquePath(dir, entry.SuggestedFilename)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	if err := d.SaveAs(path); err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	_ = d.Delete()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.Size = info.Size()
+	if a.cfg.MaxDownloadBytes > 0 && entry.Size > a.cfg.MaxDownloadBytes {
+		_ = os.Remove(path)
+		entry.Error = fmt.Sprintf("%s: %d > %d bytes", errDownloadTooLarge, entry.Size, a.cfg.MaxDownloadBytes)
+		return entry
+	}
+
+	sum, mimeType, err := inspectFile(path)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.Path = path
+	entry.SHA256 = sum
+	entry.MimeType = mimeType
+	return entry
+}
+
+func (a *PlaywrightAdapter) saveDownloads(c *downloadCollector, downloads []playwright.Download) []model.Download {
+	if len(downloads) == 0 {
+		return nil
+	}
+	out := make([]model.Download, 0, len(downloads))
+	for _, d := range downloads {
+		out = append(out, a.saveDownload(c, d))
+	}
+	return out
+}
+
+// uniquePath returns a path inside dir for name that does not clobber an
+// existing file, appending " (n)" before the extension when needed.
+func uniquePath(dir, name string) (string, error) {
+	name = filepath.Base(name)
+	if name == "" || name == "." || name == string(filepath.Separator) {
+		name = "download"
+	}
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	candidate := filepath.Join(dir, name)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(candidate); errors.Is(err, os.ErrNotExist) {
+			return candidate, nil
+		} else if err != nil {
+			return "", err
+		}
+		candidate = filepath.Join(dir, fmt.Sprintf("%s (%d)%s", stem, i, ext))
+	}
+}
+
+// inspectFile hashes the file at path and detects its MIME type from the
+// extension, falling back to content sniffing.
+func inspectFile(path string) (string, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", "", err
+	}
+	head = head[:n]
+
+	hash := sha256.New()
+	hash.Write(head)
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", "", err
+	}
+
+	mimeType := mime.TypeByExtension(filepath.Ext(path))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(head)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), mimeType, nil
+}
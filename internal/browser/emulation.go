@@ -0,0 +1,86 @@
+package browser
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/techbysteve/prowl4ai/internal/config"
+)
+
+// emulationKey identifies the browser context a run needs. Runs with equal
+// keys share a pooled context; the pool keeps only a few idle contexts, so
+// keys that vary per run do not pile up contexts. An empty key selects the
+// default settings.
+func emulationKey(cfg config.CrawlerRunConfig) string {
+	var parts []string
+	add := func(name, value string) {
+		if value != "" {
+			parts = append(parts, name+"="+value)
+		}
+	}
+	add("device", cfg.Device)
+	add("locale", cfg.Locale)
+	add("tz", cfg.TimezoneID)
+	add("scheme", cfg.ColorScheme)
+	if geo := cfg.Geolocation; geo != nil {
+		add("geo", fmt.Sprintf("%g,%g,%g", geo.Latitude, geo.Longitude, geo.Accuracy))
+	}
+	return strings.Join(parts, "|")
+}
+
+// contextOptions builds the options for a new browser context serving cfg,
+// applying the named device profile before explicit locale, timezone,
+// geolocation and color scheme settings.
+func contextOptions(pw *playwright.Playwright, browserCfg config.BrowserConfig, cfg config.CrawlerRunConfig) (playwright.BrowserNewContextOptions, error) {
+	opts := playwright.BrowserNewContextOptions{
+		AcceptDownloads: playwright.Bool(browserCfg.AcceptDownloads),
+	}
+	if len(browserCfg.Headers) > 0 {
+		// Match the HTTP adapter, which sends these with every request.
+		opts.ExtraHttpHeaders = maps.Clone(browserCfg.Headers)
+	}
+
+	if cfg.Device != "" {
+		device, ok := pw.Devices[cfg.Device]
+		if !ok {
+			return opts, fmt.Errorf("unknown device: %s", cfg.Device)
+		}
+		opts.UserAgent = playwright.String(device.UserAgent)
+		if device.Viewport != nil {
+			opts.Viewport = &playwright.Size{Width: device.Viewport.Width, Height: device.Viewport.Height}
+		}
+		if device.Screen != nil {
+			opts.Screen = &playwright.Size{Width: device.Screen.Width, Height: device.Screen.Height}
+		}
+		opts.DeviceScaleFactor = playwright.Float(device.DeviceScaleFactor)
+		opts.IsMobile = playwright.Bool(device.IsMobile)
+		opts.HasTouch = playwright.Bool(device.HasTouch)
+	}
+
+	if cfg.Locale != "" {
+		opts.Locale = playwright.String(cfg.Locale)
+	}
+	if cfg.TimezoneID != "" {
+		opts.TimezoneId = playwright.String(cfg.TimezoneID)
+	}
+	if geo := cfg.Geolocation; geo != nil {
+		opts.Geolocation = &playwright.Geolocation{
+			Latitude:  geo.Latitude,
+			Longitude: geo.Longitude,
+			Accuracy:  playwright.Float(geo.Accuracy),
+		}
+		opts.Permissions = []string{"geolocation"}
+	}
+	switch cfg.ColorScheme {
+	case "":
+	case "light", "dark", "no-preference":
+		scheme := playwright.ColorScheme(cfg.ColorScheme)
+		opts.ColorScheme = &scheme
+	default:
+		return opts, fmt.Errorf("unsupported color scheme: %s", cfg.ColorScheme)
+	}
+
+	return opts, nil
+}
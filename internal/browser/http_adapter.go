@@ -0,0 +1,316 @@
+package browser
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/mediatype"
+	"github.com/techbysteve/prowl4ai/internal/proxy"
+	"github.com/techbysteve/prowl4ai/internal/stderrors"
+	"golang.org/x/net/html/charset"
+)
+
+// maxRedirects matches the redirect limit browsers apply to navigations.
+const maxRedirects = 20
+
+// HTTPAdapter fetches pages with net/http and never starts a browser. It does
+// not execute JavaScript, so WaitFor, DownloadSelector and emulation settings
+// other than Locale (sent as Accept-Language) are ignored.
+type HTTPAdapter struct {
+	cfg     config.BrowserConfig
+	mu      sync.Mutex
+	ready   bool
+	client  *http.Client
+	proxies *proxy.Rotator
+}
+
+func NewHTTPAdapter(cfg config.BrowserConfig) *HTTPAdapter {
+	return &HTTPAdapter{
+		cfg: cfg,
+	}
+}
+
+// FetchProfile identifies the settings that shape this adapter's responses.
+func (a *HTTPAdapter) FetchProfile() string {
+	return fetchProfile(config.FetchModeHTTP, a.cfg)
+}
+
+func (a *HTTPAdapter) Start(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.ready {
+		return nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: a.cfg.IgnoreHTTPSErrors}
+	proxyFunc, err := a.proxyFunc()
+	if err != nil {
+		return err
+	}
+	transport.Proxy = proxyFunc
+
+	proxies, err := newProxyRotator(a.cfg)
+	if err != nil {
+		return err
+	}
+	checkProxies(ctx, proxies, a.cfg)
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return err
+	}
+	if err := loadCookies(jar, a.cfg.Cookies); err != nil {
+		return err
+	}
+
+	a.client = &http.Client{
+		Transport: transport,
+		Jar:       jar,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("%w: stopped after %d redirects", stderrors.ErrNavigationFailed, maxRedirects)
+			}
+			return nil
+		},
+	}
+	a.proxies = proxies
+	a.ready = true
+	return nil
+}
+
+// proxyKey carries the per-request proxy URL through the request context.
+type proxyKey struct{}
+
+// proxyFunc routes each request through the proxy stored in its context,
+// falling back to the launch-level proxy.
+func (a *HTTPAdapter) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	var fallback *url.URL
+	if server := launchProxyServer(a.cfg); server != "" {
+		p := config.ProxyConfig{Server: server}
+		if a.cfg.ProxyConfig != nil && a.cfg.ProxyConfig.Server == server {
+			p = *a.cfg.ProxyConfig
+		}
+		u, err := proxy.URL(p)
+		if err != nil {
+			return nil, err
+		}
+		fallback = u
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if u, ok := req.Context().Value(proxyKey{}).(*url.URL); ok {
+			return u, nil
+		}
+		return fallback, nil
+	}, nil
+}
+
+// loadCookies seeds jar with cookies in Playwright's shape
+// (name, value and either url or domain plus optional path).
+func loadCookies(jar http.CookieJar, cookies []map[string]any) error {
+	for _, c := range cookies {
+		name, _ := c["name"].(string)
+		value, _ := c["value"].(string)
+		if name == "" {
+			continue
+		}
+		rawURL, _ := c["url"].(string)
+		if rawURL == "" {
+			domain, _ := c["domain"].(string)
+			if domain == "" {
+				return fmt.Errorf("cookie %q needs a url or domain", name)
+			}
+			path, _ := c["path"].(string)
+			if path == "" {
+				path = "/"
+			}
+			rawURL = "https://" + strings.TrimPrefix(domain, ".") + path
+		}
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return fmt.Errorf("cookie %q: %w", name, err)
+		}
+		cookie := &http.Cookie{Name: name, Value: value, Path: "/"}
+		if path, _ := c["path"].(string); path != "" {
+			cookie.Path = path
+		}
+		if domain, _ := c["domain"].(string); domain != "" {
+			cookie.Domain = domain
+		}
+		jar.SetCookies(u, []*http.Cookie{cookie})
+	}
+	return nil
+}
+
+func (a *HTTPAdapter) FetchHTML(ctx context.Context, url string, cfg config.CrawlerRunConfig) (FetchResult, error) {
+	a.mu.Lock()
+	if !a.ready || a.client == nil {
+		a.mu.Unlock()
+		return FetchResult{}, stderrors.ErrBrowserNotStarted
+	}
+	client := a.client
+	proxies := a.proxies
+	a.mu.Unlock()
+
+	requestProxy, err := selectProxy(proxies, cfg, url)
+	if err != nil {
+		return FetchResult{}, err
+	}
+
+	timeoutMs := cfg.PageTimeoutMs
+	if timeoutMs <= 0 {
+		timeoutMs = config.DefaultPageTimeoutMs
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeoutMs)*time.Millisecond)
+	defer cancel()
+	proxyServer := launchProxyServer(a.cfg)
+	if requestProxy != nil {
+		proxyURL, err := proxy.URL(*requestProxy)
+		if err != nil {
+			return FetchResult{}, err
+		}
+		ctx = context.WithValue(ctx, proxyKey{}, proxyURL)
+		proxyServer = requestProxy.Server
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return FetchResult{}, fmt.Errorf("%w: %v", stderrors.ErrInvalidURL, err)
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	agent, err := pickUserAgent(a.cfg, "", "")
+	if err != nil {
+		return FetchResult{}, err
+	}
+	if agent.UserAgent != "" {
+		req.Header.Set("User-Agent", agent.UserAgent)
+	}
+	for k, v := range agent.Headers {
+		req.Header.Set(k, v)
+	}
+	if cfg.Locale != "" {
+		req.Header.Set("Accept-Language", cfg.Locale)
+	}
+	for k, v := range a.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	reportProxy(proxies, requestProxy, err)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return FetchResult{}, fmt.Errorf("%w: %v", stderrors.ErrTimeout, err)
+		}
+		return FetchResult{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := a.readBody(resp)
+	if err != nil {
+		return FetchResult{}, err
+	}
+
+	headers := make(map[string][]string, len(resp.Header))
+	for k, v := range resp.Header {
+		headers[strings.ToLower(k)] = v
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+
+	result := FetchResult{
+		StatusCode:      resp.StatusCode,
+		RedirectedURL:   resp.Request.URL.String(),
+		ResponseHeaders: headers,
+		ContentType:     contentType,
+		Proxy:           proxyServer,
+		UserAgent:       agent.UserAgent,
+	}
+	if !mediatype.IsHTML(contentType) {
+		result.Body = body
+		return result, nil
+	}
+
+	decoded, err := charset.NewReader(bytes.NewReader(body), contentType)
+	if err != nil {
+		return FetchResult{}, err
+	}
+	html, err := io.ReadAll(decoded)
+	if err != nil {
+		return FetchResult{}, err
+	}
+	result.HTML = string(html)
+	return result, nil
+}
+
+// readBody decompresses the response and enforces MaxResponseBytes.
+func (a *HTTPAdapter) readBody(resp *http.Response) ([]byte, error) {
+	var reader io.Reader = resp.Body
+	switch strings.ToLower(resp.Header.Get("Content-Encoding")) {
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = gz
+	case "deflate":
+		reader = newDeflateReader(resp.Body)
+	}
+
+	if a.cfg.MaxResponseBytes <= 0 {
+		return io.ReadAll(reader)
+	}
+	body, err := io.ReadAll(io.LimitReader(reader, a.cfg.MaxResponseBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > a.cfg.MaxResponseBytes {
+		return nil, fmt.Errorf("response body exceeds %d bytes", a.cfg.MaxResponseBytes)
+	}
+	return body, nil
+}
+
+// newDeflateReader handles both zlib-wrapped and raw deflate streams, since
+// servers disagree on what "deflate" means.
+func newDeflateReader(r io.Reader) io.Reader {
+	buffered := bufio.NewReader(r)
+	if header, err := buffered.Peek(2); err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		if zr, err := zlib.NewReader(buffered); err == nil {
+			return zr
+		}
+	}
+	return flate.NewReader(buffered)
+}
+
+func (a *HTTPAdapter) Close(ctx context.Context) error {
+	_ = ctx
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.ready {
+		return nil
+	}
+	a.client.CloseIdleConnections()
+	a.client = nil
+	a.ready = false
+	return nil
+}
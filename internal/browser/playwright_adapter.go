@@ -3,11 +3,19 @@ package browser
 import (
 	"context"
 	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/playwright-community/playwright-go"
 	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/mediatype"
+	"github.com/techbysteve/prowl4ai/internal/proxy"
 	"github.com/techbysteve/prowl4ai/internal/stderrors"
+	"github.com/techbysteve/prowl4ai/internal/useragent"
 )
 
 type PlaywrightAdapter struct {
@@ -15,7 +23,9 @@ type PlaywrightAdapter struct {
 	mu      sync.Mutex
 	ready   bool
 	pw      *playwright.Playwright
-	browser playwright.Browser
+	pool    *pagePool
+	proxies *proxy.Rotator
+	cookies []playwright.OptionalCookie
 }
 
 func NewPlaywrightAdapter(cfg config.BrowserConfig) *PlaywrightAdapter {
@@ -25,40 +35,77 @@ func NewPlaywrightAdapter(cfg config.BrowserConfig) *PlaywrightAdapter {
 }
 
 func (a *PlaywrightAdapter) Start(ctx context.Context) error {
-	_ = ctx
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	if a.ready {
 		return nil
 	}
+	proxies, err := newProxyRotator(a.cfg)
+	if err != nil {
+		return err
+	}
+	checkProxies(ctx, proxies, a.cfg)
+	cookies, err := playwrightCookies(a.cfg.Cookies)
+	if err != nil {
+		return err
+	}
+
 	pw, err := playwright.Run()
 	if err != nil {
 		return err
 	}
 
-	launchOptions := a.buildLaunchOptions()
-	var browser playwright.Browser
+	var browserType playwright.BrowserType
 	switch a.cfg.BrowserType {
 	case "", "chromium":
-		browser, err = pw.Chromium.Launch(launchOptions)
+		browserType = pw.Chromium
 	case "firefox":
-		browser, err = pw.Firefox.Launch(launchOptions)
+		browserType = pw.Firefox
 	case "webkit":
-		browser, err = pw.WebKit.Launch(launchOptions)
+		browserType = pw.WebKit
 	default:
 		_ = pw.Stop()
 		return fmt.Errorf("unsupported browser type: %s", a.cfg.BrowserType)
 	}
-	if err != nil {
+
+	launchOptions := a.buildLaunchOptions()
+	pool := newPagePool(
+		func() (playwright.Browser, error) {
+			return browserType.Launch(launchOptions)
+		},
+		a.cfg.MaxConcurrentPages,
+		a.cfg.MaxPagesPerContext,
+		a.cfg.RecycleAfterPages,
+		a.cfg.MaxBrowserRestarts,
+	)
+	if err := pool.start(); err != nil {
 		_ = pw.Stop()
 		return err
 	}
 	a.pw = pw
-	a.browser = browser
+	a.pool = pool
+	a.proxies = proxies
+	a.cookies = cookies
 	a.ready = true
 	return nil
 }
 
+// FetchProfile identifies the settings that shape this adapter's responses.
+func (a *PlaywrightAdapter) FetchProfile() string {
+	return fetchProfile(config.FetchModeBrowser, a.cfg)
+}
+
+// PoolStats reports page pool usage, including time spent waiting for a slot.
+func (a *PlaywrightAdapter) PoolStats() PoolStats {
+	a.mu.Lock()
+	pool := a.pool
+	a.mu.Unlock()
+	if pool == nil {
+		return PoolStats{}
+	}
+	return pool.Stats()
+}
+
 func (a *PlaywrightAdapter) buildLaunchOptions() playwright.BrowserTypeLaunchOptions {
 	opts := playwright.BrowserTypeLaunchOptions{
 		Headless: playwright.Bool(a.cfg.Headless),
@@ -76,16 +123,7 @@ func (a *PlaywrightAdapter) buildLaunchOptions() playwright.BrowserTypeLaunchOpt
 	}
 
 	if a.cfg.ProxyConfig != nil && a.cfg.ProxyConfig.Server != "" {
-		proxy := &playwright.Proxy{
-			Server: a.cfg.ProxyConfig.Server,
-		}
-		if a.cfg.ProxyConfig.Username != "" {
-			proxy.Username = playwright.String(a.cfg.ProxyConfig.Username)
-		}
-		if a.cfg.ProxyConfig.Password != "" {
-			proxy.Password = playwright.String(a.cfg.ProxyConfig.Password)
-		}
-		opts.Proxy = proxy
+		opts.Proxy = playwrightProxy(*a.cfg.ProxyConfig)
 	} else if a.cfg.Proxy != "" {
 		opts.Proxy = &playwright.Proxy{Server: a.cfg.Proxy}
 	}
@@ -101,20 +139,152 @@ func (a *PlaywrightAdapter) buildLaunchOptions() playwright.BrowserTypeLaunchOpt
 	return opts
 }
 
+func playwrightProxy(cfg config.ProxyConfig) *playwright.Proxy {
+	proxy := &playwright.Proxy{
+		Server: cfg.Server,
+	}
+	if cfg.Username != "" {
+		proxy.Username = playwright.String(cfg.Username)
+	}
+	if cfg.Password != "" {
+		proxy.Password = playwright.String(cfg.Password)
+	}
+	return proxy
+}
+
 func (a *PlaywrightAdapter) FetchHTML(ctx context.Context, url string, cfg config.CrawlerRunConfig) (FetchResult, error) {
 	a.mu.Lock()
-	if !a.ready || a.browser == nil {
+	if !a.ready || a.pool == nil {
 		a.mu.Unlock()
 		return FetchResult{}, stderrors.ErrBrowserNotStarted
 	}
-	b := a.browser
+	pool := a.pool
+	pw := a.pw
+	proxies := a.proxies
 	a.mu.Unlock()
 
-	page, err := b.NewPage()
+	requestProxy, err := selectProxy(proxies, cfg, url)
+	if err != nil {
+		return FetchResult{}, err
+	}
+	opts, err := contextOptions(pw, a.cfg, cfg)
+	if err != nil {
+		return FetchResult{}, err
+	}
+	key := emulationKey(cfg)
+	if requestProxy != nil {
+		opts.Proxy = playwrightProxy(*requestProxy)
+		key += "|proxy=" + requestProxy.Username + "@" + requestProxy.Server
+	}
+	pooled, err := pool.acquire(ctx, key, func(b playwright.Browser) (playwright.BrowserContext, useragent.Agent, error) {
+		return a.newContext(b, opts)
+	})
 	if err != nil {
 		return FetchResult{}, err
 	}
-	defer page.Close()
+	defer pooled.release()
+
+	if hints := pooled.clientHints(); len(hints) > 0 {
+		// Client hints go with navigations only, like the per-run headers
+		// that override them; subresource origins never see them.
+		headers := maps.Clone(hints)
+		maps.Copy(headers, cfg.Headers)
+		cfg.Headers = headers
+	}
+	result, err := a.fetchPage(ctx, pooled.Page, url, cfg)
+	reportProxy(proxies, requestProxy, err)
+	if err != nil && pooled.crashed() {
+		// The next acquire relaunches the browser, so callers may retry.
+		return FetchResult{}, fmt.Errorf("%w: %w", stderrors.ErrBrowserCrashed, err)
+	}
+	if err != nil {
+		return FetchResult{}, err
+	}
+	result.UserAgent = pooled.userAgent()
+	result.Proxy = launchProxyServer(a.cfg)
+	if requestProxy != nil {
+		result.Proxy = requestProxy.Server
+	}
+	return result, nil
+}
+
+// newContext opens a context with opts. Unless a device profile already set
+// the user agent, it applies the configured or generated one; generated
+// agents are limited to desktop platforms to match the default viewport. The
+// returned agent carries the client-hint headers fetchPage sends with
+// navigations.
+func (a *PlaywrightAdapter) newContext(b playwright.Browser, opts playwright.BrowserNewContextOptions) (playwright.BrowserContext, useragent.Agent, error) {
+	var agent useragent.Agent
+	if opts.UserAgent == nil {
+		var err error
+		agent, err = pickUserAgent(a.cfg, a.cfg.BrowserType, "desktop")
+		if err != nil {
+			return nil, useragent.Agent{}, err
+		}
+		if agent.UserAgent != "" {
+			opts.UserAgent = playwright.String(agent.UserAgent)
+		}
+	} else {
+		agent.UserAgent = *opts.UserAgent
+	}
+
+	bctx, err := b.NewContext(opts)
+	if err != nil {
+		return nil, useragent.Agent{}, err
+	}
+	if len(a.cookies) > 0 {
+		if err := bctx.AddCookies(a.cookies); err != nil {
+			_ = bctx.Close()
+			return nil, useragent.Agent{}, err
+		}
+	}
+	return bctx, agent, nil
+}
+
+// playwrightCookies converts configured cookies, which use the shape
+// loadCookies reads, into Playwright's.
+func playwrightCookies(cookies []map[string]any) ([]playwright.OptionalCookie, error) {
+	var out []playwright.OptionalCookie
+	for _, c := range cookies {
+		name, _ := c["name"].(string)
+		if name == "" {
+			continue
+		}
+		value, _ := c["value"].(string)
+		cookie := playwright.OptionalCookie{Name: name, Value: value}
+		if rawURL, _ := c["url"].(string); rawURL != "" {
+			cookie.URL = playwright.String(rawURL)
+		} else {
+			domain, _ := c["domain"].(string)
+			if domain == "" {
+				return nil, fmt.Errorf("cookie %q needs a url or domain", name)
+			}
+			path, _ := c["path"].(string)
+			if path == "" {
+				path = "/"
+			}
+			cookie.Domain = playwright.String(domain)
+			cookie.Path = playwright.String(path)
+		}
+		out = append(out, cookie)
+	}
+	return out, nil
+}
+
+// Healthy reports whether the adapter is started and its browser is connected.
+// An unhealthy adapter relaunches the browser on the next FetchHTML.
+func (a *PlaywrightAdapter) Healthy() bool {
+	a.mu.Lock()
+	pool := a.pool
+	a.mu.Unlock()
+	return pool != nil && pool.healthy()
+}
+
+func (a *PlaywrightAdapter) fetchPage(ctx context.Context, page playwright.Page, url string, cfg config.CrawlerRunConfig) (FetchResult, error) {
+	var downloads *downloadCollector
+	if a.cfg.AcceptDownloads {
+		downloads = newDownloadCollector(page, a.cfg.MaxDownloadBytes)
+	}
 
 	waitUntil := cfg.WaitUntil
 	if waitUntil == "" {
@@ -126,6 +296,12 @@ func (a *PlaywrightAdapter) FetchHTML(ctx context.Context, url string, cfg confi
 		timeoutMs = config.DefaultPageTimeoutMs
 	}
 	timeout := float64(timeoutMs)
+	if len(cfg.Headers) > 0 {
+		if err := routeDocumentHeaders(page, cfg.Headers); err != nil {
+			return FetchResult{}, err
+		}
+	}
+
 	waitUntilState := playwright.WaitUntilState(waitUntil)
 	resp, err := page.Goto(
 		url,
@@ -135,9 +311,40 @@ func (a *PlaywrightAdapter) FetchHTML(ctx context.Context, url string, cfg confi
 		},
 	)
 	if err != nil {
+		// Chromium turns navigations to attachments (most PDFs in headless mode)
+		// into downloads; fetch those bodies through the page's request context.
+		if isDownloadStartingError(err) {
+			if downloads != nil {
+				return a.fetchNavigationDownload(downloads, timeout)
+			}
+			return fetchRaw(page, url, timeout)
+		}
 		return FetchResult{}, err
 	}
 
+	if resp != nil && resp.Status() == http.StatusNotModified {
+		return FetchResult{
+			StatusCode:      resp.Status(),
+			RedirectedURL:   page.URL(),
+			ResponseHeaders: singleValueHeaders(resp.Headers()),
+		}, nil
+	}
+	if resp != nil {
+		if contentType := resp.Headers()["content-type"]; !mediatype.IsHTML(contentType) {
+			body, err := resp.Body()
+			if err != nil {
+				return FetchResult{}, err
+			}
+			return FetchResult{
+				StatusCode:      resp.Status(),
+				RedirectedURL:   page.URL(),
+				ResponseHeaders: singleValueHeaders(resp.Headers()),
+				ContentType:     contentType,
+				Body:            body,
+			}, nil
+		}
+	}
+
 	if cfg.WaitFor != "" {
 		waitForTimeout := timeout
 		if cfg.WaitForTimeoutMs > 0 {
@@ -150,6 +357,27 @@ func (a *PlaywrightAdapter) FetchHTML(ctx context.Context, url string, cfg confi
 		}
 	}
 
+	var pending []playwright.Download
+	if cfg.DownloadSelector != "" {
+		if downloads == nil {
+			return FetchResult{}, stderrors.ErrDownloadsDisabled
+		}
+		if err := page.Locator(cfg.DownloadSelector).First().Click(playwright.LocatorClickOptions{
+			Timeout: &timeout,
+		}); err != nil {
+			return FetchResult{}, err
+		}
+		downloadTimeout := timeout
+		if cfg.DownloadTimeoutMs > 0 {
+			downloadTimeout = float64(cfg.DownloadTimeoutMs)
+		}
+		d, ok := downloads.wait(time.Duration(downloadTimeout) * time.Millisecond)
+		if !ok {
+			return FetchResult{}, fmt.Errorf("%w: no download after clicking %q", stderrors.ErrTimeout, cfg.DownloadSelector)
+		}
+		pending = append(pending, d)
+	}
+
 	// if context is already canceled return immediatly
 	select {
 	case <-ctx.Done():
@@ -168,16 +396,91 @@ func (a *PlaywrightAdapter) FetchHTML(ctx context.Context, url string, cfg confi
 	}
 	if resp != nil {
 		result.StatusCode = resp.Status()
-		headers := make(map[string][]string, len(resp.Headers()))
-		for k, v := range resp.Headers() {
-			headers[k] = []string{v}
-		}
-		result.ResponseHeaders = headers
+		result.ResponseHeaders = singleValueHeaders(resp.Headers())
+		result.ContentType = resp.Headers()["content-type"]
+	}
+	if downloads != nil {
+		result.Downloads = a.saveDownloads(downloads, append(pending, downloads.drain()...))
 	}
 
 	return result, nil
 }
 
+// fetchNavigationDownload saves the file a navigation turned into a download
+// and returns its bytes as the response body so extraction can still run.
+func (a *PlaywrightAdapter) fetchNavigationDownload(downloads *downloadCollector, timeout float64) (FetchResult, error) {
+	d, ok := downloads.wait(time.Duration(timeout) * time.Millisecond)
+	if !ok {
+		return FetchResult{}, fmt.Errorf("%w: waiting for download", stderrors.ErrTimeout)
+	}
+
+	saved := a.saveDownloads(downloads, append([]playwright.Download{d}, downloads.drain()...))
+	result := FetchResult{
+		RedirectedURL: d.URL(),
+		Downloads:     saved,
+	}
+	if primary := saved[0]; primary.Error == "" {
+		body, err := os.ReadFile(primary.Path)
+		if err != nil {
+			return FetchResult{}, err
+		}
+		result.Body = body
+		result.ContentType = primary.MimeType
+	}
+	return result, nil
+}
+
+func fetchRaw(page playwright.Page, url string, timeout float64) (FetchResult, error) {
+	resp, err := page.Request().Get(url, playwright.APIRequestContextGetOptions{
+		Timeout: &timeout,
+	})
+	if err != nil {
+		return FetchResult{}, err
+	}
+	defer resp.Dispose()
+
+	body, err := resp.Body()
+	if err != nil {
+		return FetchResult{}, err
+	}
+	return FetchResult{
+		StatusCode:      resp.Status(),
+		RedirectedURL:   resp.URL(),
+		ResponseHeaders: singleValueHeaders(resp.Headers()),
+		ContentType:     resp.Headers()["content-type"],
+		Body:            body,
+	}, nil
+}
+
+// routeDocumentHeaders adds headers to the page's top-level navigation
+// requests only, so per-run and conditional headers do not reach subresources.
+func routeDocumentHeaders(page playwright.Page, headers map[string]string) error {
+	return page.Route("**/*", func(route playwright.Route) {
+		req := route.Request()
+		if !req.IsNavigationRequest() || req.Frame() != page.MainFrame() {
+			_ = route.Continue()
+			return
+		}
+		merged := req.Headers()
+		for k, v := range headers {
+			merged[strings.ToLower(k)] = v
+		}
+		_ = route.Continue(playwright.RouteContinueOptions{Headers: merged})
+	})
+}
+
+func singleValueHeaders(in map[string]string) map[string][]string {
+	headers := make(map[string][]string, len(in))
+	for k, v := range in {
+		headers[k] = []string{v}
+	}
+	return headers
+}
+
+func isDownloadStartingError(err error) bool {
+	return strings.Contains(err.Error(), "Download is starting")
+}
+
 func (a *PlaywrightAdapter) Close(ctx context.Context) error {
 	_ = ctx
 
@@ -186,15 +489,15 @@ func (a *PlaywrightAdapter) Close(ctx context.Context) error {
 		a.mu.Unlock()
 		return nil
 	}
-	b := a.browser
+	pool := a.pool
 	pw := a.pw
-	a.browser = nil
+	a.pool = nil
 	a.pw = nil
 	a.ready = false
 	a.mu.Unlock()
 
-	if b != nil {
-		if err := b.Close(); err != nil {
+	if pool != nil {
+		if err := pool.close(); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,103 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/stderrors"
+)
+
+func newTestAdapter(t *testing.T, maxRestarts int) (*PlaywrightAdapter, *fakeLauncher) {
+	t.Helper()
+	pool, launcher := newTestPool(t, 4, 0, 0, maxRestarts)
+	return &PlaywrightAdapter{cfg: config.DefaultBrowserConfig(), ready: true, pool: pool}, launcher
+}
+
+func fetch(a *PlaywrightAdapter) error {
+	_, err := a.FetchHTML(context.Background(), "https://example.com/", config.DefaultCrawlerRunConfig())
+	return err
+}
+
+func TestFetchHTMLRelaunchesCrashedBrowser(t *testing.T) {
+	a, launcher := newTestAdapter(t, 3)
+	if err := fetch(a); err != nil {
+		t.Fatalf("FetchHTML: %v", err)
+	}
+
+	launcher.last().kill()
+	if a.Healthy() {
+		t.Error("Healthy reports true with a crashed browser")
+	}
+	result, err := a.FetchHTML(context.Background(), "https://example.com/", config.DefaultCrawlerRunConfig())
+	if err != nil {
+		t.Fatalf("FetchHTML after a crash: %v", err)
+	}
+	if result.HTML == "" {
+		t.Error("FetchHTML after a crash returned no HTML")
+	}
+	if !a.Healthy() {
+		t.Error("Healthy reports false after the relaunch")
+	}
+	stats := a.PoolStats()
+	if stats.BrowsersLaunched != 2 || stats.BrowserCrashes != 1 {
+		t.Errorf("stats = %+v, want 2 launched, 1 crash", stats)
+	}
+}
+
+func TestFetchHTMLStopsRelaunchingAfterMaxRestarts(t *testing.T) {
+	a, launcher := newTestAdapter(t, 2)
+	for i := range 2 {
+		launcher.last().kill()
+		if err := fetch(a); err != nil {
+			t.Fatalf("FetchHTML after crash %d: %v", i+1, err)
+		}
+	}
+
+	launcher.last().kill()
+	err := fetch(a)
+	if !errors.Is(err, stderrors.ErrBrowserUnavailable) {
+		t.Fatalf("FetchHTML with the restart budget spent = %v, want ErrBrowserUnavailable", err)
+	}
+	if stderrors.IsRetryable(err) {
+		t.Errorf("IsRetryable(%v) = true", err)
+	}
+	if n := len(launcher.browsers); n != 3 {
+		t.Errorf("launched %d browsers, want 3", n)
+	}
+
+	// The budget covers a sliding window, so old restarts free it up again.
+	a.pool.mu.Lock()
+	for i := range a.pool.restarts {
+		a.pool.restarts[i] = a.pool.restarts[i].Add(-restartWindow)
+	}
+	a.pool.mu.Unlock()
+	if err := fetch(a); err != nil {
+		t.Fatalf("FetchHTML after the window passed: %v", err)
+	}
+}
+
+func TestPlaywrightCookies(t *testing.T) {
+	cookies, err := playwrightCookies([]map[string]any{
+		{"name": "a", "value": "1", "url": "https://example.com/"},
+		{"name": "b", "value": "2", "domain": ".example.com"},
+		{"value": "unnamed"},
+	})
+	if err != nil {
+		t.Fatalf("playwrightCookies: %v", err)
+	}
+	if len(cookies) != 2 {
+		t.Fatalf("got %d cookies, want 2", len(cookies))
+	}
+	if cookies[0].URL == nil || *cookies[0].URL != "https://example.com/" || cookies[0].Domain != nil {
+		t.Errorf("url cookie = %+v", cookies[0])
+	}
+	if cookies[1].Domain == nil || *cookies[1].Domain != ".example.com" || cookies[1].Path == nil || *cookies[1].Path != "/" {
+		t.Errorf("domain cookie = %+v", cookies[1])
+	}
+
+	if _, err := playwrightCookies([]map[string]any{{"name": "c", "value": "3"}}); err == nil {
+		t.Error("a cookie without url or domain was accepted")
+	}
+}
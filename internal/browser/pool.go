@@ -0,0 +1,543 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/stderrors"
+	"github.com/techbysteve/prowl4ai/internal/useragent"
+)
+
+// PoolStats reports page pool usage and queue-wait metrics.
+type PoolStats struct {
+	ActivePages      int           `json:"active_pages"`
+	WaitingRequests  int           `json:"waiting_requests"`
+	OpenContexts     int           `json:"open_contexts"`
+	PagesServed      int64         `json:"pages_served"`
+	BrowsersLaunched int           `json:"browsers_launched"`
+	BrowsersRecycled int           `json:"browsers_recycled"`
+	BrowserCrashes   int           `json:"browser_crashes"`
+	TotalQueueWait   time.Duration `json:"total_queue_wait"`
+	MaxQueueWait     time.Duration `json:"max_queue_wait"`
+}
+
+// AvgQueueWait is the mean time a request waited for a page slot.
+func (s PoolStats) AvgQueueWait() time.Duration {
+	if s.PagesServed == 0 {
+		return 0
+	}
+	return s.TotalQueueWait / time.Duration(s.PagesServed)
+}
+
+// PoolStatser is implemented by adapters backed by a page pool.
+type PoolStatser interface {
+	PoolStats() PoolStats
+}
+
+// contextFactory opens a browser context for a pool key and reports the user
+// agent the context presents.
+type contextFactory func(b playwright.Browser) (playwright.BrowserContext, useragent.Agent, error)
+
+// pagePool hands out pages from shared browser contexts. It caps concurrent
+// pages per browser and per context and replaces the browser after
+// recycleAfter pages or when it disconnects. Pages with the same key share a
+// context, and with it cookies, storage and auth state. Idle contexts stay
+// open for later pages with their key, up to maxIdleContexts per browser and
+// for at most idleContextTTL, and are closed with their browser. Replaced
+// browsers are closed once their last page is released.
+type pagePool struct {
+	launch       func() (playwright.Browser, error)
+	maxRestarts  int
+	slots        chan struct{}
+	pagesPerCtx  int
+	recycleAfter int
+	crashes      atomic.Int64
+	mu           sync.Mutex
+	current      *pooledBrowser
+	launching    *launchCall
+	draining     map[*pooledBrowser]struct{}
+	closed       bool
+	waiting      int
+	stats        PoolStats
+	// recovering is set while the current browser has crashed and no
+	// replacement has launched yet; restarts holds the launch times that
+	// count against maxRestarts.
+	recovering bool
+	restarts   []time.Time
+}
+
+type pooledBrowser struct {
+	browser  playwright.Browser
+	contexts map[string][]*pooledContext
+	served   int
+	active   int
+	closing  atomic.Bool
+	dead     atomic.Bool
+}
+
+// launchCall is a browser launch in flight; concurrent callers wait on done
+// and share its result.
+type launchCall struct {
+	done chan struct{}
+	pb   *pooledBrowser
+	err  error
+}
+
+type pooledContext struct {
+	context playwright.BrowserContext
+	key     string
+	agent   useragent.Agent
+	active  int
+	// idleSince is when active last dropped to zero.
+	idleSince time.Time
+}
+
+// pooledPage is a page checked out of the pool. Call release when done.
+type pooledPage struct {
+	playwright.Page
+	pool    *pagePool
+	browser *pooledBrowser
+	ctx     *pooledContext
+	once    sync.Once
+}
+
+// restartBackoff is the base delay between browser relaunch attempts.
+// restartWindow is the span over which maxRestarts caps crash relaunches.
+const (
+	restartBackoff = 500 * time.Millisecond
+	restartWindow  = 10 * time.Minute
+)
+
+// Idle contexts kept per browser for reuse, and how long each is kept.
+const (
+	maxIdleContexts = 4
+	idleContextTTL  = time.Minute
+)
+
+var errPoolClosed = errors.New("page pool closed")
+
+func newPagePool(launch func() (playwright.Browser, error), maxPages, pagesPerCtx, recycleAfter, maxRestarts int) *pagePool {
+	if maxPages <= 0 {
+		maxPages = config.DefaultMaxConcurrentPages
+	}
+	return &pagePool{
+		launch:       launch,
+		maxRestarts:  maxRestarts,
+		slots:        make(chan struct{}, maxPages),
+		pagesPerCtx:  pagesPerCtx,
+		recycleAfter: recycleAfter,
+		draining:     map[*pooledBrowser]struct{}{},
+	}
+}
+
+// start launches the first browser so configuration errors surface early.
+func (p *pagePool) start() error {
+	_, err := p.browser(context.Background())
+	return err
+}
+
+// acquire waits for a free page slot and opens a page in a context matching key.
+func (p *pagePool) acquire(ctx context.Context, key string, newContext contextFactory) (*pooledPage, error) {
+	p.mu.Lock()
+	p.waiting++
+	p.mu.Unlock()
+
+	began := time.Now()
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		p.mu.Lock()
+		p.waiting--
+		p.mu.Unlock()
+		return nil, ctx.Err()
+	}
+	waited := time.Since(began)
+
+	p.mu.Lock()
+	p.waiting--
+	p.stats.TotalQueueWait += waited
+	if waited > p.stats.MaxQueueWait {
+		p.stats.MaxQueueWait = waited
+	}
+	p.mu.Unlock()
+
+	pb, pc, err := p.reserve(ctx, key, newContext)
+	if err != nil {
+		<-p.slots
+		return nil, err
+	}
+
+	page, err := pc.context.NewPage()
+	if err != nil {
+		p.releaseSlot(pb, pc)
+		return nil, err
+	}
+	return &pooledPage{Page: page, pool: p, browser: pb, ctx: pc}, nil
+}
+
+// reserve picks the browser and context for a new page and counts it
+// against their limits. Launching a browser and opening a context are
+// Playwright round trips, so both run without p.mu held.
+func (p *pagePool) reserve(ctx context.Context, key string, newContext contextFactory) (*pooledBrowser, *pooledContext, error) {
+	for {
+		pb, err := p.browser(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		p.mu.Lock()
+		if p.current != pb || pb.dead.Load() {
+			// Replaced or crashed since it was handed out; try again.
+			p.mu.Unlock()
+			continue
+		}
+		if evicted := p.evictIdleLocked(pb, time.Now()); len(evicted) > 0 {
+			go closeContexts(pb, evicted)
+		}
+		var pc *pooledContext
+		for _, candidate := range pb.contexts[key] {
+			if p.pagesPerCtx <= 0 || candidate.active < p.pagesPerCtx {
+				pc = candidate
+				break
+			}
+		}
+		// Count the page before unlocking so pb is not closed under it.
+		pb.active++
+		if pc != nil {
+			pc.active++
+			p.servedLocked(pb)
+			p.mu.Unlock()
+			return pb, pc, nil
+		}
+		p.mu.Unlock()
+
+		bctx, agent, err := newContext(pb.browser)
+
+		p.mu.Lock()
+		if err != nil {
+			closeNow := p.releaseBrowserLocked(pb)
+			p.mu.Unlock()
+			if closeNow {
+				go closeBrowser(pb)
+			}
+			return nil, nil, err
+		}
+		pc = &pooledContext{context: bctx, key: key, agent: agent, active: 1}
+		pb.contexts[key] = append(pb.contexts[key], pc)
+		p.servedLocked(pb)
+		p.mu.Unlock()
+		return pb, pc, nil
+	}
+}
+
+// servedLocked counts a page handed out by pb and retires pb once it has
+// served recycleAfter pages.
+func (p *pagePool) servedLocked(pb *pooledBrowser) {
+	pb.served++
+	p.stats.PagesServed++
+	if p.recycleAfter > 0 && pb.served >= p.recycleAfter && !pb.closing.Load() {
+		p.retireLocked(pb)
+		p.stats.BrowsersRecycled++
+	}
+}
+
+// browser returns the current browser, launching a replacement when there
+// is none or the previous one disconnected. Concurrent callers share one
+// launch.
+func (p *pagePool) browser(ctx context.Context) (*pooledBrowser, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, errPoolClosed
+	}
+	if p.current != nil && !p.current.dead.Load() {
+		pb := p.current
+		p.mu.Unlock()
+		return pb, nil
+	}
+	if p.current != nil {
+		p.recovering = true
+		p.retireLocked(p.current)
+	}
+	call := p.launching
+	if call == nil {
+		call = &launchCall{done: make(chan struct{})}
+		p.launching = call
+		relaunch := p.stats.BrowsersLaunched > 0
+		// The launch outlives a cancelled caller so waiters still get a browser.
+		go p.runLaunch(call, relaunch, p.recovering)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.pb, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// runLaunch launches a browser for call. Relaunches are retried with
+// backoff up to maxRestarts times; while recovering from a crash every
+// attempt also counts against the restart budget, and a spent budget fails
+// the launch with ErrBrowserUnavailable.
+func (p *pagePool) runLaunch(call *launchCall, relaunch, recovering bool) {
+	attempts := 1
+	if relaunch && p.maxRestarts > 0 {
+		attempts = p.maxRestarts
+	}
+	var (
+		b   playwright.Browser
+		err error
+	)
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if recovering && !p.takeRestart(time.Now()) {
+			err = fmt.Errorf("%w: %d restarts in the last %v", stderrors.ErrBrowserUnavailable, p.maxRestarts, restartWindow)
+			attempts = 1
+			break
+		}
+		if b, err = p.launch(); err == nil {
+			break
+		}
+		if attempt < attempts {
+			time.Sleep(time.Duration(attempt) * restartBackoff)
+		}
+	}
+
+	p.mu.Lock()
+	defer func() {
+		p.launching = nil
+		p.mu.Unlock()
+		close(call.done)
+	}()
+	if err != nil {
+		call.err = err
+		if attempts > 1 {
+			call.err = fmt.Errorf("%w: relaunch failed after %d attempts: %w", stderrors.ErrBrowserUnavailable, attempts, err)
+		}
+		return
+	}
+
+	pb := &pooledBrowser{
+		browser:  b,
+		contexts: map[string][]*pooledContext{},
+	}
+	// Playwright runs event handlers on its connection loop, which must not
+	// block on p.mu: the pool may be waiting on that loop for a reply.
+	b.OnDisconnected(func(playwright.Browser) {
+		if !pb.dead.Swap(true) && !pb.closing.Load() {
+			p.crashes.Add(1)
+		}
+	})
+	if p.closed {
+		call.err = errPoolClosed
+		go closeBrowser(pb)
+		return
+	}
+	p.current = pb
+	p.recovering = false
+	p.stats.BrowsersLaunched++
+	call.pb = pb
+}
+
+// takeRestart records a crash relaunch at now, reporting false instead when
+// maxRestarts relaunches already happened within restartWindow.
+func (p *pagePool) takeRestart(now time.Time) bool {
+	if p.maxRestarts <= 0 {
+		return true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.restarts = slices.DeleteFunc(p.restarts, func(t time.Time) bool {
+		return now.Sub(t) >= restartWindow
+	})
+	if len(p.restarts) >= p.maxRestarts {
+		return false
+	}
+	p.restarts = append(p.restarts, now)
+	return true
+}
+
+// healthy reports whether the pool has a live browser or can launch one.
+func (p *pagePool) healthy() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return !p.closed && (p.current == nil || !p.current.dead.Load())
+}
+
+// retireLocked stops handing out pages from pb and closes it once idle.
+func (p *pagePool) retireLocked(pb *pooledBrowser) {
+	if p.current == pb {
+		p.current = nil
+	}
+	pb.closing.Store(true)
+	if pb.active == 0 {
+		go closeBrowser(pb)
+		return
+	}
+	p.draining[pb] = struct{}{}
+}
+
+// releaseSlot uncounts a page, keeping its context for reuse once idle and
+// closing its browser once retired and idle, and frees the page's slot.
+func (p *pagePool) releaseSlot(pb *pooledBrowser, pc *pooledContext) {
+	p.mu.Lock()
+	pc.active--
+	var evicted []*pooledContext
+	if pc.active == 0 {
+		pc.idleSince = time.Now()
+		if !pb.closing.Load() {
+			evicted = p.evictIdleLocked(pb, pc.idleSince)
+		}
+	}
+	closeNow := p.releaseBrowserLocked(pb)
+	p.mu.Unlock()
+
+	if closeNow {
+		go closeBrowser(pb)
+	} else {
+		closeContexts(pb, evicted)
+	}
+	<-p.slots
+}
+
+// evictIdleLocked removes pb's idle contexts that are past idleContextTTL
+// or beyond the maxIdleContexts most recently used, and returns them for the
+// caller to close once p.mu is released.
+func (p *pagePool) evictIdleLocked(pb *pooledBrowser, now time.Time) []*pooledContext {
+	var idle []*pooledContext
+	for _, contexts := range pb.contexts {
+		for _, pc := range contexts {
+			if pc.active == 0 {
+				idle = append(idle, pc)
+			}
+		}
+	}
+	if len(idle) == 0 {
+		return nil
+	}
+	slices.SortFunc(idle, func(a, b *pooledContext) int {
+		return b.idleSince.Compare(a.idleSince)
+	})
+	var evicted []*pooledContext
+	for i, pc := range idle {
+		if i < maxIdleContexts && now.Sub(pc.idleSince) < idleContextTTL {
+			continue
+		}
+		contexts := slices.DeleteFunc(pb.contexts[pc.key], func(c *pooledContext) bool { return c == pc })
+		if len(contexts) == 0 {
+			delete(pb.contexts, pc.key)
+		} else {
+			pb.contexts[pc.key] = contexts
+		}
+		evicted = append(evicted, pc)
+	}
+	return evicted
+}
+
+// closeContexts closes evicted contexts of pb unless the browser is gone.
+func closeContexts(pb *pooledBrowser, contexts []*pooledContext) {
+	if pb.dead.Load() {
+		return
+	}
+	for _, pc := range contexts {
+		_ = pc.context.Close()
+	}
+}
+
+// releaseBrowserLocked uncounts a page of pb and reports whether pb is
+// retired and now idle, so the caller should close it.
+func (p *pagePool) releaseBrowserLocked(pb *pooledBrowser) bool {
+	pb.active--
+	if _, retired := p.draining[pb]; !retired || pb.active > 0 {
+		return false
+	}
+	delete(p.draining, pb)
+	return true
+}
+
+// userAgent returns the user agent of the page's context.
+func (pp *pooledPage) userAgent() string {
+	return pp.ctx.agent.UserAgent
+}
+
+// clientHints returns the client-hint headers matching the page's user agent.
+func (pp *pooledPage) clientHints() map[string]string {
+	return pp.ctx.agent.Headers
+}
+
+// crashed reports whether the page's browser disconnected unexpectedly.
+func (pp *pooledPage) crashed() bool {
+	return pp.browser.dead.Load() || !pp.browser.browser.IsConnected()
+}
+
+// release closes the page and returns its slot to the pool.
+func (pp *pooledPage) release() {
+	pp.once.Do(func() {
+		_ = pp.Page.Close()
+		pp.pool.releaseSlot(pp.browser, pp.ctx)
+	})
+}
+
+// Stats returns a snapshot of pool usage.
+func (p *pagePool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.BrowserCrashes = int(p.crashes.Load())
+	stats.WaitingRequests = p.waiting
+	for _, pb := range p.browsersLocked() {
+		stats.ActivePages += pb.active
+		for _, contexts := range pb.contexts {
+			stats.OpenContexts += len(contexts)
+		}
+	}
+	return stats
+}
+
+func (p *pagePool) browsersLocked() []*pooledBrowser {
+	out := make([]*pooledBrowser, 0, len(p.draining)+1)
+	if p.current != nil {
+		out = append(out, p.current)
+	}
+	for pb := range p.draining {
+		out = append(out, pb)
+	}
+	return out
+}
+
+// close shuts down every browser the pool owns, including ones still draining.
+func (p *pagePool) close() error {
+	p.mu.Lock()
+	browsers := p.browsersLocked()
+	for _, pb := range browsers {
+		pb.closing.Store(true)
+	}
+	p.current = nil
+	p.draining = map[*pooledBrowser]struct{}{}
+	p.closed = true
+	p.mu.Unlock()
+
+	var errs []error
+	for _, pb := range browsers {
+		if err := closeBrowser(pb); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func closeBrowser(pb *pooledBrowser) error {
+	if pb.dead.Load() {
+		return nil
+	}
+	return pb.browser.Close()
+}
@@ -0,0 +1,284 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/techbysteve/prowl4ai/internal/useragent"
+)
+
+// The fakes implement the parts of the Playwright interfaces the pool and
+// PlaywrightAdapter.fetchPage use; anything else panics.
+
+type fakePage struct {
+	playwright.Page
+	browser *fakeBrowser
+	html    string
+}
+
+func (p *fakePage) Close(...playwright.PageCloseOptions) error { return nil }
+func (p *fakePage) URL() string                                { return "https://example.com/" }
+
+func (p *fakePage) Goto(string, ...playwright.PageGotoOptions) (playwright.Response, error) {
+	if !p.browser.IsConnected() {
+		return nil, errors.New("target closed")
+	}
+	return nil, nil
+}
+
+func (p *fakePage) Content() (string, error) {
+	if !p.browser.IsConnected() {
+		return "", errors.New("target closed")
+	}
+	return p.html, nil
+}
+
+type fakeContext struct {
+	playwright.BrowserContext
+	browser *fakeBrowser
+	closed  atomic.Bool
+}
+
+func (c *fakeContext) NewPage() (playwright.Page, error) {
+	return &fakePage{browser: c.browser, html: "<html><body>ok</body></html>"}, nil
+}
+
+func (c *fakeContext) Close(...playwright.BrowserContextCloseOptions) error {
+	c.closed.Store(true)
+	return nil
+}
+
+type fakeBrowser struct {
+	playwright.Browser
+	mu        sync.Mutex
+	handlers  []func(playwright.Browser)
+	contexts  []*fakeContext
+	connected atomic.Bool
+}
+
+func (b *fakeBrowser) OnDisconnected(fn func(playwright.Browser)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, fn)
+}
+
+func (b *fakeBrowser) IsConnected() bool { return b.connected.Load() }
+
+func (b *fakeBrowser) NewContext(...playwright.BrowserNewContextOptions) (playwright.BrowserContext, error) {
+	c := &fakeContext{browser: b}
+	b.mu.Lock()
+	b.contexts = append(b.contexts, c)
+	b.mu.Unlock()
+	return c, nil
+}
+
+func (b *fakeBrowser) Close(...playwright.BrowserCloseOptions) error {
+	b.kill()
+	return nil
+}
+
+// kill disconnects the browser as a crash would.
+func (b *fakeBrowser) kill() {
+	if !b.connected.Swap(false) {
+		return
+	}
+	b.mu.Lock()
+	handlers := b.handlers
+	b.mu.Unlock()
+	for _, fn := range handlers {
+		fn(b)
+	}
+}
+
+func (b *fakeBrowser) openContexts() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	open := 0
+	for _, c := range b.contexts {
+		if !c.closed.Load() {
+			open++
+		}
+	}
+	return open
+}
+
+// fakeLauncher launches fakeBrowsers and remembers them.
+type fakeLauncher struct {
+	mu       sync.Mutex
+	browsers []*fakeBrowser
+}
+
+func (l *fakeLauncher) launch() (playwright.Browser, error) {
+	b := &fakeBrowser{}
+	b.connected.Store(true)
+	l.mu.Lock()
+	l.browsers = append(l.browsers, b)
+	l.mu.Unlock()
+	return b, nil
+}
+
+func (l *fakeLauncher) last() *fakeBrowser {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.browsers[len(l.browsers)-1]
+}
+
+func openContext(b playwright.Browser) (playwright.BrowserContext, useragent.Agent, error) {
+	c, err := b.NewContext()
+	return c, useragent.Agent{}, err
+}
+
+func newTestPool(t *testing.T, maxPages, pagesPerCtx, recycleAfter, maxRestarts int) (*pagePool, *fakeLauncher) {
+	t.Helper()
+	launcher := &fakeLauncher{}
+	p := newPagePool(launcher.launch, maxPages, pagesPerCtx, recycleAfter, maxRestarts)
+	if err := p.start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	t.Cleanup(func() { _ = p.close() })
+	return p, launcher
+}
+
+func mustAcquire(t *testing.T, p *pagePool, key string) *pooledPage {
+	t.Helper()
+	pp, err := p.acquire(context.Background(), key, openContext)
+	if err != nil {
+		t.Fatalf("acquire(%q): %v", key, err)
+	}
+	return pp
+}
+
+func TestPagePoolReusesIdleContexts(t *testing.T) {
+	p, launcher := newTestPool(t, 8, 4, 0, 3)
+
+	first := mustAcquire(t, p, "a")
+	first.release()
+	second := mustAcquire(t, p, "a")
+	if second.ctx != first.ctx {
+		t.Error("a sequential page with the same key got a new context")
+	}
+	second.release()
+
+	// Idle contexts beyond the cap are closed, least recently used first.
+	for _, key := range []string{"b", "c", "d", "e"} {
+		mustAcquire(t, p, key).release()
+	}
+	if !first.ctx.context.(*fakeContext).closed.Load() {
+		t.Error("the least recently used idle context is still open")
+	}
+	if got := launcher.last().openContexts(); got != maxIdleContexts {
+		t.Errorf("open contexts = %d, want %d", got, maxIdleContexts)
+	}
+
+	// Contexts idle past the TTL are closed on the next use of the pool.
+	p.mu.Lock()
+	for _, contexts := range p.current.contexts {
+		for _, pc := range contexts {
+			pc.idleSince = pc.idleSince.Add(-2 * idleContextTTL)
+		}
+	}
+	p.mu.Unlock()
+	mustAcquire(t, p, "f").release()
+	if stats := p.Stats(); stats.OpenContexts != 1 {
+		t.Errorf("OpenContexts = %d after the TTL, want 1", stats.OpenContexts)
+	}
+}
+
+func TestPagePoolContextCap(t *testing.T) {
+	p, _ := newTestPool(t, 8, 2, 0, 3)
+
+	pages := []*pooledPage{mustAcquire(t, p, "a"), mustAcquire(t, p, "a"), mustAcquire(t, p, "a")}
+	if pages[0].ctx != pages[1].ctx {
+		t.Error("concurrent pages under the cap did not share a context")
+	}
+	if pages[2].ctx == pages[0].ctx {
+		t.Error("a context holds more pages than MaxPagesPerContext")
+	}
+	if stats := p.Stats(); stats.ActivePages != 3 || stats.OpenContexts != 2 {
+		t.Errorf("stats = %+v, want 3 active pages in 2 contexts", stats)
+	}
+	for _, pp := range pages {
+		pp.release()
+	}
+}
+
+func TestPagePoolPageCapAndQueueWait(t *testing.T) {
+	p, _ := newTestPool(t, 2, 0, 0, 3)
+
+	held := []*pooledPage{mustAcquire(t, p, "a"), mustAcquire(t, p, "b")}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := p.acquire(ctx, "c", openContext); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("acquire over the page cap = %v, want a deadline error", err)
+	}
+
+	const wait = 50 * time.Millisecond
+	got := make(chan *pooledPage)
+	go func() {
+		pp, err := p.acquire(context.Background(), "c", openContext)
+		if err != nil {
+			t.Error(err)
+		}
+		got <- pp
+	}()
+	deadline := time.Now().Add(time.Second)
+	for p.Stats().WaitingRequests != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("the blocked acquire is not counted as waiting")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(wait)
+	held[0].release()
+	(<-got).release()
+	held[1].release()
+
+	stats := p.Stats()
+	if stats.WaitingRequests != 0 || stats.ActivePages != 0 {
+		t.Errorf("stats = %+v, want no waiting or active pages", stats)
+	}
+	if stats.PagesServed != 3 {
+		t.Errorf("PagesServed = %d, want 3", stats.PagesServed)
+	}
+	if stats.MaxQueueWait < wait || stats.TotalQueueWait < stats.MaxQueueWait {
+		t.Errorf("queue wait total %v, max %v; want max >= %v", stats.TotalQueueWait, stats.MaxQueueWait, wait)
+	}
+	if avg := stats.AvgQueueWait(); avg != stats.TotalQueueWait/3 {
+		t.Errorf("AvgQueueWait = %v, want %v", avg, stats.TotalQueueWait/3)
+	}
+}
+
+func TestPagePoolRecyclesBrowser(t *testing.T) {
+	p, launcher := newTestPool(t, 8, 0, 2, 3)
+
+	first := mustAcquire(t, p, "a")
+	second := mustAcquire(t, p, "a")
+	third := mustAcquire(t, p, "a")
+	if third.browser == first.browser {
+		t.Fatal("the browser served more pages than RecycleAfterPages")
+	}
+	old := launcher.browsers[0]
+	first.release()
+	if !old.IsConnected() {
+		t.Error("the recycled browser closed while a page was still open")
+	}
+	second.release()
+	third.release()
+	deadline := time.Now().Add(time.Second)
+	for old.IsConnected() {
+		if time.Now().After(deadline) {
+			t.Fatal("the recycled browser was not closed once idle")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	stats := p.Stats()
+	if stats.BrowsersLaunched != 2 || stats.BrowsersRecycled != 1 || stats.BrowserCrashes != 0 {
+		t.Errorf("stats = %+v, want 2 launched, 1 recycled, no crashes", stats)
+	}
+}
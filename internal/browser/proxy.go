@@ -0,0 +1,69 @@
+package browser
+
+import (
+	"context"
+	"time"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/proxy"
+)
+
+// proxyHealthCheckTimeout bounds each proxy health check request.
+const proxyHealthCheckTimeout = 10 * time.Second
+
+// newProxyRotator returns a rotator over cfg.Proxies, or nil when no proxy
+// pool is configured.
+func newProxyRotator(cfg config.BrowserConfig) (*proxy.Rotator, error) {
+	if len(cfg.Proxies) == 0 {
+		return nil, nil
+	}
+	return proxy.NewRotator(cfg.Proxies, cfg.ProxyRotation, cfg.ProxyMaxFailures)
+}
+
+// checkProxies drops unhealthy proxies from rotation when a health check URL
+// is configured.
+func checkProxies(ctx context.Context, rotator *proxy.Rotator, cfg config.BrowserConfig) {
+	if rotator == nil || cfg.ProxyHealthCheckURL == "" {
+		return
+	}
+	rotator.CheckHealth(ctx, cfg.ProxyHealthCheckURL, proxyHealthCheckTimeout)
+}
+
+// selectProxy picks the per-request proxy: the run's explicit proxy first,
+// then the rotation pool. A nil result means the launch-level proxy, if any,
+// applies.
+func selectProxy(rotator *proxy.Rotator, cfg config.CrawlerRunConfig, url string) (*config.ProxyConfig, error) {
+	if cfg.ProxyConfig != nil && cfg.ProxyConfig.Server != "" {
+		return cfg.ProxyConfig, nil
+	}
+	if rotator == nil {
+		return nil, nil
+	}
+	picked, err := rotator.Pick(url)
+	if err != nil {
+		return nil, err
+	}
+	return &picked, nil
+}
+
+// reportProxy feeds the outcome of a request back into the rotation pool.
+func reportProxy(rotator *proxy.Rotator, used *config.ProxyConfig, err error) {
+	if rotator == nil || used == nil {
+		return
+	}
+	if proxy.IsProxyError(err) {
+		rotator.ReportFailure(used.Server)
+		return
+	}
+	if err == nil {
+		rotator.ReportSuccess(used.Server)
+	}
+}
+
+// launchProxyServer returns the proxy applied to every request at launch.
+func launchProxyServer(cfg config.BrowserConfig) string {
+	if cfg.ProxyConfig != nil && cfg.ProxyConfig.Server != "" {
+		return cfg.ProxyConfig.Server
+	}
+	return cfg.Proxy
+}
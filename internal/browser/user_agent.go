@@ -0,0 +1,24 @@
+package browser
+
+import (
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/useragent"
+)
+
+// pickUserAgent returns the user agent and client-hint headers for a new
+// context or request. In random mode a fresh agent is generated, limited to
+// browsers matching engine when the generator config names none and to
+// platform when it is set; otherwise the configured static user agent is used.
+func pickUserAgent(cfg config.BrowserConfig, engine, platform string) (useragent.Agent, error) {
+	if cfg.UserAgentMode != config.UserAgentModeRandom {
+		return useragent.Agent{UserAgent: cfg.UserAgent}, nil
+	}
+	opts := useragent.OptionsFromMap(cfg.UserAgentGeneratorCfg)
+	if len(opts.Browsers) == 0 && engine != "" {
+		opts.Browsers = useragent.BrowsersForEngine(engine)
+	}
+	if platform != "" {
+		opts.Platforms = []string{platform}
+	}
+	return useragent.Generate(opts)
+}
@@ -0,0 +1,185 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/model"
+	"github.com/techbysteve/prowl4ai/internal/stderrors"
+	"github.com/techbysteve/prowl4ai/internal/urlutil"
+)
+
+// Entry is one cached fetch. It keeps the raw response so extraction can be
+// re-run with different settings, plus the result of the last extraction.
+type Entry struct {
+	Key             string              `json:"key"`
+	URL             string              `json:"url"`
+	FetchedAt       time.Time           `json:"fetched_at"`
+	StatusCode      int                 `json:"status_code,omitempty"`
+	RedirectedURL   string              `json:"redirected_url,omitempty"`
+	ResponseHeaders map[string][]string `json:"response_headers,omitempty"`
+	ContentType     string              `json:"content_type,omitempty"`
+	HTML            string              `json:"html,omitempty"`
+	Body            []byte              `json:"body,omitempty"`
+	Proxy           string              `json:"proxy,omitempty"`
+	UserAgent       string              `json:"user_agent,omitempty"`
+	Downloads       []model.Download    `json:"downloads,omitempty"`
+	// ETag and LastModified are the response validators used to revalidate
+	// the entry with a conditional request.
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	// ExtractKey identifies the run settings Result was extracted with.
+	ExtractKey string             `json:"extract_key,omitempty"`
+	Result     *model.CrawlResult `json:"result,omitempty"`
+}
+
+// Stats summarizes a store's contents.
+type Stats struct {
+	Entries int       `json:"entries"`
+	Bytes   int64     `json:"bytes"`
+	Oldest  time.Time `json:"oldest,omitzero"`
+	Newest  time.Time `json:"newest,omitzero"`
+}
+
+// Store persists cache entries by key.
+type Store interface {
+	// Get returns the entry for key; the boolean is false on a miss.
+	Get(key string) (Entry, bool, error)
+	Put(entry Entry) error
+	// Purge removes entries fetched before cutoff, or every entry when
+	// cutoff is zero, and reports how many were removed.
+	Purge(cutoff time.Time) (int, error)
+	Stats() (Stats, error)
+	// Walk calls fn for every entry until fn returns an error.
+	Walk(fn func(Entry) error) error
+}
+
+// Readable reports whether mode serves cached entries.
+func Readable(mode string) bool {
+	return mode == config.CacheModeEnabled || mode == config.CacheModeReadOnly
+}
+
+// Writable reports whether mode stores fresh fetches.
+func Writable(mode string) bool {
+	return mode == config.CacheModeEnabled || mode == config.CacheModeWriteOnly
+}
+
+// ValidMode reports whether mode is a known cache mode. Empty means the default.
+func ValidMode(mode string) bool {
+	switch mode {
+	case "", config.CacheModeEnabled, config.CacheModeDisabled, config.CacheModeReadOnly,
+		config.CacheModeWriteOnly, config.CacheModeBypass:
+		return true
+	}
+	return false
+}
+
+// fetchSettings are the run settings that change what the fetch returns.
+// Extraction-only settings are left out so they can change without a refetch.
+type fetchSettings struct {
+	WaitUntil        string              `json:"wait_until,omitempty"`
+	WaitFor          string              `json:"wait_for,omitempty"`
+	DownloadSelector string              `json:"download_selector,omitempty"`
+	Device           string              `json:"device,omitempty"`
+	Locale           string              `json:"locale,omitempty"`
+	TimezoneID       string              `json:"timezone_id,omitempty"`
+	Geolocation      *config.Geolocation `json:"geolocation,omitempty"`
+	ColorScheme      string              `json:"color_scheme,omitempty"`
+	ProxyConfig      *config.ProxyConfig `json:"proxy_config,omitempty"`
+	Headers          map[string]string   `json:"headers,omitempty"`
+	Profile          string              `json:"profile,omitempty"`
+}
+
+// Key identifies the fetch of url under cfg by an adapter with the given
+// fetch profile (see browser.FetchProfiler): the normalized URL plus a hash
+// of the fetch-relevant settings. Adapters without a profile share entries.
+// An unparsable url fails with stderrors.ErrInvalidURL.
+func Key(url string, cfg config.CrawlerRunConfig, profile string) (string, error) {
+	normalized, err := urlutil.Normalize(url)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", stderrors.ErrInvalidURL, err)
+	}
+	hash, err := hashJSON(fetchSettings{
+		WaitUntil:        cfg.WaitUntil,
+		WaitFor:          cfg.WaitFor,
+		DownloadSelector: cfg.DownloadSelector,
+		Device:           cfg.Device,
+		Locale:           cfg.Locale,
+		TimezoneID:       cfg.TimezoneID,
+		Geolocation:      cfg.Geolocation,
+		ColorScheme:      cfg.ColorScheme,
+		ProxyConfig:      cfg.ProxyConfig,
+		Headers:          cfg.Headers,
+		Profile:          profile,
+	})
+	if err != nil {
+		return "", err
+	}
+	return normalized + "|" + hash, nil
+}
+
+// Fresh reports whether entry may be served without revalidation. A
+// non-positive maxAge never expires entries.
+func (e Entry) Fresh(maxAge time.Duration) bool {
+	return maxAge <= 0 || time.Since(e.FetchedAt) < maxAge
+}
+
+// SetValidators records the ETag and Last-Modified response headers.
+func (e *Entry) SetValidators(headers map[string][]string) {
+	for k, v := range headers {
+		if len(v) == 0 {
+			continue
+		}
+		switch strings.ToLower(k) {
+		case "etag":
+			e.ETag = v[0]
+		case "last-modified":
+			e.LastModified = v[0]
+		}
+	}
+}
+
+// ConditionalHeaders returns the If-None-Match and If-Modified-Since headers
+// that revalidate entry, or nil when it has no validators.
+func (e Entry) ConditionalHeaders() map[string]string {
+	if e.ETag == "" && e.LastModified == "" {
+		return nil
+	}
+	headers := map[string]string{}
+	if e.ETag != "" {
+		headers["If-None-Match"] = e.ETag
+	}
+	if e.LastModified != "" {
+		headers["If-Modified-Since"] = e.LastModified
+	}
+	return headers
+}
+
+// ExtractKey identifies the full run configuration a cached result was
+// extracted with, ignoring the cache and robots.txt settings. It is empty for
+// runs with custom content filters, whose results cannot be keyed.
+func ExtractKey(cfg config.CrawlerRunConfig) (string, error) {
+	if len(cfg.CustomContentFilters) > 0 {
+		return "", nil
+	}
+	cfg.CacheMode = ""
+	cfg.CacheMaxAgeMs = 0
+	cfg.CheckRobotsTxt = false
+	return hashJSON(cfg)
+}
+
+// hashJSON hashes v's JSON form. Marshaling fails on settings JSON cannot
+// represent, such as a NaN coordinate.
+func hashJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("cache: hash settings: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8]), nil
+}
@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/stderrors"
+)
+
+func TestKey(t *testing.T) {
+	base := config.DefaultCrawlerRunConfig()
+	key, err := Key("https://Example.com/a?b=1#frag", base, "")
+	if err != nil {
+		t.Fatalf("Key: %v", err)
+	}
+
+	same := []struct {
+		name    string
+		url     string
+		cfg     func(*config.CrawlerRunConfig)
+		profile string
+	}{
+		{"normalized url", "https://example.com/a?b=1", nil, ""},
+		{"extraction setting", "https://example.com/a?b=1", func(c *config.CrawlerRunConfig) { c.EnableLinks = true }, ""},
+		{"cache mode", "https://example.com/a?b=1", func(c *config.CrawlerRunConfig) { c.CacheMode = config.CacheModeEnabled }, ""},
+	}
+	for _, tt := range same {
+		cfg := base
+		if tt.cfg != nil {
+			tt.cfg(&cfg)
+		}
+		got, err := Key(tt.url, cfg, tt.profile)
+		if err != nil {
+			t.Fatalf("%s: Key: %v", tt.name, err)
+		}
+		if got != key {
+			t.Errorf("%s: key changed to %q, want %q", tt.name, got, key)
+		}
+	}
+
+	different := []struct {
+		name    string
+		url     string
+		cfg     func(*config.CrawlerRunConfig)
+		profile string
+	}{
+		{"other url", "https://example.com/b", nil, ""},
+		{"locale", "https://example.com/a?b=1", func(c *config.CrawlerRunConfig) { c.Locale = "de-DE" }, ""},
+		{"headers", "https://example.com/a?b=1", func(c *config.CrawlerRunConfig) { c.Headers = map[string]string{"X-A": "1"} }, ""},
+		{"fetch profile", "https://example.com/a?b=1", nil, `{"mode":"http"}`},
+	}
+	for _, tt := range different {
+		cfg := base
+		if tt.cfg != nil {
+			tt.cfg(&cfg)
+		}
+		got, err := Key(tt.url, cfg, tt.profile)
+		if err != nil {
+			t.Fatalf("%s: Key: %v", tt.name, err)
+		}
+		if got == key {
+			t.Errorf("%s: key did not change", tt.name)
+		}
+	}
+}
+
+func TestKeyErrors(t *testing.T) {
+	cfg := config.DefaultCrawlerRunConfig()
+	if _, err := Key("://bad", cfg, ""); !errors.Is(err, stderrors.ErrInvalidURL) {
+		t.Errorf("Key(bad url) error = %v, want ErrInvalidURL", err)
+	}
+	cfg.Geolocation = &config.Geolocation{Latitude: math.NaN()}
+	if _, err := Key("https://example.com/", cfg, ""); err == nil {
+		t.Error("Key with a NaN latitude returned no error")
+	}
+}
+
+type keepAll struct{}
+
+func (keepAll) Filter(html string) (string, error) { return html, nil }
+
+func TestExtractKey(t *testing.T) {
+	base := config.DefaultCrawlerRunConfig()
+	key, err := ExtractKey(base)
+	if err != nil || key == "" {
+		t.Fatalf("ExtractKey = %q, %v", key, err)
+	}
+
+	ignored := base
+	ignored.CacheMode = config.CacheModeEnabled
+	ignored.CacheMaxAgeMs = 1000
+	ignored.CheckRobotsTxt = true
+	if got, _ := ExtractKey(ignored); got != key {
+		t.Errorf("cache and robots settings changed the key to %q, want %q", got, key)
+	}
+
+	changed := base
+	changed.EnableTables = true
+	if got, _ := ExtractKey(changed); got == key {
+		t.Error("EnableTables did not change the key")
+	}
+
+	custom := base
+	custom.CustomContentFilters = []config.ContentFilter{keepAll{}}
+	if got, err := ExtractKey(custom); got != "" || err != nil {
+		t.Errorf("ExtractKey with custom filters = %q, %v, want empty", got, err)
+	}
+
+	nan := base
+	nan.ContentFilters = []config.ContentFilterConfig{{Type: config.ContentFilterPruning, Threshold: math.NaN()}}
+	if _, err := ExtractKey(nan); err == nil {
+		t.Error("ExtractKey with a NaN threshold returned no error")
+	}
+}
@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/techbysteve/prowl4ai/internal/fsutil"
+)
+
+// errCorruptEntry marks entry files that cannot be decoded.
+var errCorruptEntry = errors.New("cache: corrupt entry")
+
+// DiskStore keeps one JSON file per entry in a directory.
+type DiskStore struct {
+	dir string
+}
+
+// DefaultDir returns the cache directory used when none is configured.
+func DefaultDir() string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "prowl4ai")
+	}
+	return filepath.Join(os.TempDir(), "prowl4ai-cache")
+}
+
+// OpenDisk opens a disk store in dir, creating it if needed. An empty dir
+// means DefaultDir.
+func OpenDisk(dir string) (*DiskStore, error) {
+	if dir == "" {
+		dir = DefaultDir()
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskStore{dir: dir}, nil
+}
+
+// Dir returns the directory backing the store.
+func (s *DiskStore) Dir() string {
+	return s.dir
+}
+
+func (s *DiskStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (s *DiskStore) Get(key string) (Entry, bool, error) {
+	entry, err := readEntry(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, err
+	}
+	if entry.Key != key {
+		return Entry{}, false, nil
+	}
+	return entry, true, nil
+}
+
+// Put writes entry atomically so concurrent readers never see partial files.
+func (s *DiskStore) Put(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(s.path(entry.Key), data)
+}
+
+// Purge also removes entries that cannot be decoded, whatever the cutoff.
+func (s *DiskStore) Purge(cutoff time.Time) (int, error) {
+	removed := 0
+	remove := func(path string) error {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		removed++
+		return nil
+	}
+	err := s.walkFiles(func(path string, entry Entry) error {
+		if !cutoff.IsZero() && !entry.FetchedAt.Before(cutoff) {
+			return nil
+		}
+		return remove(path)
+	}, remove)
+	return removed, err
+}
+
+func (s *DiskStore) Stats() (Stats, error) {
+	var stats Stats
+	err := s.walkFiles(func(path string, entry Entry) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		stats.Entries++
+		stats.Bytes += info.Size()
+		if stats.Oldest.IsZero() || entry.FetchedAt.Before(stats.Oldest) {
+			stats.Oldest = entry.FetchedAt
+		}
+		if entry.FetchedAt.After(stats.Newest) {
+			stats.Newest = entry.FetchedAt
+		}
+		return nil
+	}, nil)
+	return stats, err
+}
+
+func (s *DiskStore) Walk(fn func(Entry) error) error {
+	return s.walkFiles(func(_ string, entry Entry) error {
+		return fn(entry)
+	}, nil)
+}
+
+// walkFiles calls fn for every entry file. Files that cannot be decoded, such
+// as ones truncated by a crash, are passed to corrupt instead, or skipped
+// when it is nil, so one bad file does not break the whole store.
+func (s *DiskStore) walkFiles(fn func(path string, entry Entry) error, corrupt func(path string) error) error {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(s.dir, f.Name())
+		entry, err := readEntry(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if errors.Is(err, errCorruptEntry) {
+			if corrupt != nil {
+				if err := corrupt(path); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(path, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readEntry(path string) (Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Entry{}, err
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return Entry{}, fmt.Errorf("%w %s: %w", errCorruptEntry, filepath.Base(path), err)
+	}
+	return entry, nil
+}
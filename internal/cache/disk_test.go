@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDiskStoreSkipsCorruptEntries(t *testing.T) {
+	store, err := OpenDisk(t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenDisk: %v", err)
+	}
+	now := time.Now().UTC()
+	for _, entry := range []Entry{
+		{Key: "old", URL: "https://example.com/old", FetchedAt: now.Add(-time.Hour)},
+		{Key: "new", URL: "https://example.com/new", FetchedAt: now},
+	} {
+		if err := store.Put(entry); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+	bad := filepath.Join(store.Dir(), "truncated.json")
+	if err := os.WriteFile(bad, []byte(`{"key":"tr`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if entry, ok, err := store.Get("new"); err != nil || !ok || entry.URL != "https://example.com/new" {
+		t.Errorf("Get(new) = %+v, %v, %v", entry, ok, err)
+	}
+	stats, err := store.Stats()
+	if err != nil || stats.Entries != 2 {
+		t.Errorf("Stats = %+v, %v, want 2 entries", stats, err)
+	}
+	walked := 0
+	if err := store.Walk(func(Entry) error { walked++; return nil }); err != nil || walked != 2 {
+		t.Errorf("Walk visited %d entries, err %v, want 2", walked, err)
+	}
+
+	removed, err := store.Purge(now.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Purge removed %d, want the old and the corrupt entry", removed)
+	}
+	if _, err := os.Stat(bad); !os.IsNotExist(err) {
+		t.Errorf("corrupt file still exists: %v", err)
+	}
+	if _, ok, _ := store.Get("new"); !ok {
+		t.Error("Purge removed an entry newer than the cutoff")
+	}
+}
@@ -0,0 +1,247 @@
+package change
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/techbysteve/prowl4ai/internal/model"
+)
+
+// contextLines is the number of unchanged lines shown around each hunk.
+const contextLines = 3
+
+// Report describes how a page's content changed between two crawls.
+type Report struct {
+	OldURL  string `json:"old_url"`
+	NewURL  string `json:"new_url"`
+	Changed bool   `json:"changed"`
+	// Similarity is the share of word pairs the two versions have in common,
+	// from 0 (disjoint) to 1 (identical). See Similarity.
+	Similarity      float64 `json:"similarity"`
+	SimHashDistance int     `json:"simhash_distance"`
+	OldHash         string  `json:"old_hash"`
+	NewHash         string  `json:"new_hash"`
+	// Diff is a unified diff of the normalized Markdown, empty when unchanged.
+	Diff string `json:"diff,omitempty"`
+}
+
+// Fingerprint returns the content hash and SimHash of a result's Markdown.
+func Fingerprint(markdown string) (string, string) {
+	return ContentHash(markdown), FormatSimHash(SimHash(markdown))
+}
+
+// Diff compares the Markdown of two crawl results.
+func Diff(old, new model.CrawlResult) Report {
+	oldLines := NormalizeLines(string(old.Markdown))
+	newLines := NormalizeLines(string(new.Markdown))
+	edits := diffLines(oldLines, newLines)
+
+	report := Report{
+		OldURL:          old.URL,
+		NewURL:          new.URL,
+		OldHash:         ContentHash(string(old.Markdown)),
+		NewHash:         ContentHash(string(new.Markdown)),
+		Similarity:      Similarity(string(old.Markdown), string(new.Markdown)),
+		SimHashDistance: Distance(SimHash(string(old.Markdown)), SimHash(string(new.Markdown))),
+	}
+	report.Changed = report.OldHash != report.NewHash
+	if report.Changed {
+		report.Diff = unified(old.URL, new.URL, edits)
+	}
+	return report
+}
+
+type op int
+
+const (
+	opEqual op = iota
+	opDelete
+	opInsert
+)
+
+type edit struct {
+	op   op
+	line string
+}
+
+// diffLines computes a shortest edit script from a to b with Myers'
+// algorithm. Each step keeps only the frontier window it can read back, so
+// memory grows with the square of the edit distance rather than the input.
+func diffLines(a, b []string) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]edit, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		edits = append(edits, edit{opEqual, line})
+	}
+	edits = append(edits, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, edit{opEqual, line})
+	}
+	return edits
+}
+
+// frontier holds the furthest x reached on diagonals -d-1..d+1 before step d.
+type frontier struct {
+	d int
+	x []int
+}
+
+func (f frontier) at(k int) int {
+	return f.x[k+f.d+1]
+}
+
+func myers(a, b []string) []edit {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+	limit := n + m
+	v := make([]int, 2*limit+3)
+	offset := limit + 1
+	var trace []frontier
+
+	for d := 0; d <= limit; d++ {
+		window := make([]int, 2*d+3)
+		copy(window, v[offset-d-1:offset+d+2])
+		trace = append(trace, frontier{d: d, x: window})
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+	return nil
+}
+
+func backtrack(a, b []string, trace []frontier) []edit {
+	var reversed []edit
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		f := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && f.at(k-1) < f.at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := f.at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, edit{opEqual, a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, edit{opInsert, b[y-1]})
+			} else {
+				reversed = append(reversed, edit{opDelete, a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	edits := make([]edit, len(reversed))
+	for i, e := range reversed {
+		edits[len(reversed)-1-i] = e
+	}
+	return edits
+}
+
+// unified renders edits as a unified diff with contextLines of context.
+func unified(oldName, newName string, edits []edit) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+
+	for start := 0; start < len(edits); {
+		// Find the next change and the end of the hunk around it, merging
+		// changes separated by at most 2*contextLines equal lines.
+		first := start
+		for first < len(edits) && edits[first].op == opEqual {
+			first++
+		}
+		if first == len(edits) {
+			break
+		}
+		end := first
+		for i := first; i < len(edits); i++ {
+			if edits[i].op != opEqual {
+				end = i + 1
+				continue
+			}
+			if i-end >= 2*contextLines {
+				break
+			}
+		}
+
+		hunkStart := max(first-contextLines, start)
+		hunkEnd := min(end+contextLines, len(edits))
+		writeHunk(&sb, edits, hunkStart, hunkEnd)
+		start = hunkEnd
+	}
+	return sb.String()
+}
+
+func writeHunk(sb *strings.Builder, edits []edit, from, to int) {
+	oldLine, newLine := 1, 1
+	for _, e := range edits[:from] {
+		if e.op != opInsert {
+			oldLine++
+		}
+		if e.op != opDelete {
+			newLine++
+		}
+	}
+	oldCount, newCount := 0, 0
+	for _, e := range edits[from:to] {
+		if e.op != opInsert {
+			oldCount++
+		}
+		if e.op != opDelete {
+			newCount++
+		}
+	}
+	if oldCount == 0 {
+		oldLine--
+	}
+	if newCount == 0 {
+		newLine--
+	}
+
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", oldLine, oldCount, newLine, newCount)
+	for _, e := range edits[from:to] {
+		switch e.op {
+		case opEqual:
+			sb.WriteString(" ")
+		case opDelete:
+			sb.WriteString("-")
+		case opInsert:
+			sb.WriteString("+")
+		}
+		sb.WriteString(e.line)
+		sb.WriteString("\n")
+	}
+}
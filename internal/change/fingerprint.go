@@ -0,0 +1,125 @@
+package change
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// shingleSize is the number of consecutive words hashed as one SimHash feature.
+const shingleSize = 3
+
+// NormalizeLines splits markdown into lines with surrounding whitespace
+// trimmed, internal runs of whitespace collapsed and blank lines dropped, so
+// that reflowed or re-indented content compares equal.
+func NormalizeLines(markdown string) []string {
+	var out []string
+	for _, line := range strings.Split(markdown, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// ContentHash returns the hex SHA-256 of the normalized markdown.
+func ContentHash(markdown string) string {
+	sum := sha256.Sum256([]byte(strings.Join(NormalizeLines(markdown), "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// SimHash returns a 64-bit locality-sensitive fingerprint of the markdown's
+// words: near-identical documents differ in only a few bits.
+func SimHash(markdown string) uint64 {
+	words := words(markdown)
+	if len(words) == 0 {
+		return 0
+	}
+
+	size := min(shingleSize, len(words))
+	var weights [64]int
+	for i := 0; i+size <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+size], " ")))
+		sum := h.Sum64()
+		for bit := range weights {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for bit, w := range weights {
+		if w > 0 {
+			fingerprint |= 1 << bit
+		}
+	}
+	return fingerprint
+}
+
+// Similarity is the Dice coefficient of the word-pair multisets of two
+// documents, from 0 (nothing shared) to 1 (same words in the same order). A
+// single edited word in a long page barely moves it.
+func Similarity(a, b string) float64 {
+	pairsA, pairsB := wordPairs(a), wordPairs(b)
+	total := len(pairsA) + len(pairsB)
+	if total == 0 {
+		return 1
+	}
+	counts := make(map[string]int, len(pairsA))
+	for _, p := range pairsA {
+		counts[p]++
+	}
+	shared := 0
+	for _, p := range pairsB {
+		if counts[p] > 0 {
+			counts[p]--
+			shared++
+		}
+	}
+	return float64(2*shared) / float64(total)
+}
+
+// wordPairs returns consecutive word pairs, or the single word of a one-word
+// document.
+func wordPairs(markdown string) []string {
+	w := words(markdown)
+	if len(w) == 1 {
+		return w
+	}
+	pairs := make([]string, 0, len(w))
+	for i := 1; i < len(w); i++ {
+		pairs = append(pairs, w[i-1]+" "+w[i])
+	}
+	return pairs
+}
+
+func words(markdown string) []string {
+	return strings.FieldsFunc(strings.ToLower(markdown), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// FormatSimHash renders a fingerprint as 16 hex digits.
+func FormatSimHash(fingerprint uint64) string {
+	return fmt.Sprintf("%016x", fingerprint)
+}
+
+// ParseSimHash reads a fingerprint written by FormatSimHash.
+func ParseSimHash(s string) (uint64, error) {
+	return strconv.ParseUint(s, 16, 64)
+}
+
+// Distance is the number of differing bits between two fingerprints.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -1,14 +1,40 @@
 package config
 
 const (
-	DefaultBrowserType    = "chromium"
-	DefaultBrowserMode    = "dedicated"
-	DefaultChromeChannel  = "chromium"
-	DefaultHost           = "localhost"
-	DefaultDebugPort      = 9222
-	DefaultViewportWidth  = 1080
-	DefaultViewportHeight = 600
-	DefaultUserAgent      = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 Chrome/116.0.0.0 Safari/537.36"
+	DefaultBrowserType        = "chromium"
+	DefaultBrowserMode        = "dedicated"
+	DefaultChromeChannel      = "chromium"
+	DefaultHost               = "localhost"
+	DefaultDebugPort          = 9222
+	DefaultViewportWidth      = 1080
+	DefaultViewportHeight     = 600
+	DefaultUserAgent          = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 Chrome/116.0.0.0 Safari/537.36"
+	DefaultMaxDownloadBytes   = 100 << 20 // 100 MiB
+	DefaultFetchMode          = FetchModeBrowser
+	DefaultMaxResponseBytes   = 50 << 20 // 50 MiB
+	DefaultMaxConcurrentPages = 8
+	DefaultMaxPagesPerContext = 4
+	DefaultMaxBrowserRestarts = 3
+	DefaultProxyRotation      = ProxyRotationRoundRobin
+	DefaultProxyMaxFailures   = 3
+)
+
+// Proxy rotation strategies for BrowserConfig.Proxies.
+const (
+	ProxyRotationRoundRobin = "round_robin"
+	ProxyRotationRandom     = "random"
+	ProxyRotationSticky     = "sticky"
+)
+
+// UserAgentModeRandom generates a realistic user agent per browser context
+// (or per request for HTTP fetches) instead of using UserAgent.
+const UserAgentModeRandom = "random"
+
+// Fetch modes select the browser.Adapter implementation.
+const (
+	FetchModeBrowser = "browser"
+	FetchModeHTTP    = "http"
+	FetchModeAuto    = "auto"
 )
 
 type ProxyConfig struct {
@@ -22,6 +48,8 @@ type BrowserConfig struct {
 	BrowserType           string            `json:"browser_type"`
 	Headless              bool              `json:"headless"`
 	BrowserMode           string            `json:"browser_mode"`
+	FetchMode             string            `json:"fetch_mode"`
+	MaxResponseBytes      int64             `json:"max_response_bytes,omitempty"`
 	UseManagedBrowser     bool              `json:"use_managed_browser"`
 	CDPURL                string            `json:"cdp_url,omitempty"`
 	BrowserContextID      string            `json:"browser_context_id,omitempty"`
@@ -34,10 +62,15 @@ type BrowserConfig struct {
 	Channel               string            `json:"channel"`
 	Proxy                 string            `json:"proxy,omitempty"`
 	ProxyConfig           *ProxyConfig      `json:"proxy_config,omitempty"`
+	Proxies               []ProxyConfig     `json:"proxies,omitempty"`
+	ProxyRotation         string            `json:"proxy_rotation,omitempty"`
+	ProxyHealthCheckURL   string            `json:"proxy_health_check_url,omitempty"`
+	ProxyMaxFailures      int               `json:"proxy_max_failures,omitempty"`
 	ViewportWidth         int               `json:"viewport_width"`
 	ViewportHeight        int               `json:"viewport_height"`
 	AcceptDownloads       bool              `json:"accept_downloads"`
 	DownloadsPath         string            `json:"downloads_path,omitempty"`
+	MaxDownloadBytes      int64             `json:"max_download_bytes,omitempty"`
 	StorageState          any               `json:"storage_state,omitempty"`
 	IgnoreHTTPSErrors     bool              `json:"ignore_https_errors"`
 	JavaScriptEnabled     bool              `json:"java_script_enabled"`
@@ -51,6 +84,10 @@ type BrowserConfig struct {
 	TextMode              bool              `json:"text_mode"`
 	LightMode             bool              `json:"light_mode"`
 	ExtraArgs             []string          `json:"extra_args,omitempty"`
+	MaxConcurrentPages    int               `json:"max_concurrent_pages"`
+	MaxPagesPerContext    int               `json:"max_pages_per_context"`
+	RecycleAfterPages     int               `json:"recycle_after_pages,omitempty"`
+	MaxBrowserRestarts    int               `json:"max_browser_restarts"`
 	DebuggingPort         int               `json:"debugging_port"`
 	Host                  string            `json:"host"`
 	EnableStealth         bool              `json:"enable_stealth"`
@@ -59,28 +96,35 @@ type BrowserConfig struct {
 
 func DefaultBrowserConfig() BrowserConfig {
 	return BrowserConfig{
-		BrowserType:       DefaultBrowserType,
-		Headless:          true,
-		BrowserMode:       DefaultBrowserMode,
-		UseManagedBrowser: false,
-		ChromeChannel:     DefaultChromeChannel,
-		Channel:           DefaultChromeChannel,
-		ViewportWidth:     DefaultViewportWidth,
-		ViewportHeight:    DefaultViewportHeight,
-		AcceptDownloads:   false,
-		IgnoreHTTPSErrors: true,
-		JavaScriptEnabled: true,
-		SleepOnClose:      false,
-		Verbose:           true,
-		Cookies:           []map[string]any{},
-		Headers:           map[string]string{},
-		UserAgent:         DefaultUserAgent,
-		TextMode:          false,
-		LightMode:         false,
-		ExtraArgs:         []string{},
-		DebuggingPort:     DefaultDebugPort,
-		Host:              DefaultHost,
-		EnableStealth:     false,
-		InitScripts:       []string{},
+		BrowserType:        DefaultBrowserType,
+		Headless:           true,
+		BrowserMode:        DefaultBrowserMode,
+		FetchMode:          DefaultFetchMode,
+		MaxResponseBytes:   DefaultMaxResponseBytes,
+		UseManagedBrowser:  false,
+		ChromeChannel:      DefaultChromeChannel,
+		Channel:            DefaultChromeChannel,
+		ViewportWidth:      DefaultViewportWidth,
+		ViewportHeight:     DefaultViewportHeight,
+		AcceptDownloads:    false,
+		MaxDownloadBytes:   DefaultMaxDownloadBytes,
+		IgnoreHTTPSErrors:  true,
+		JavaScriptEnabled:  true,
+		SleepOnClose:       false,
+		Verbose:            true,
+		Cookies:            []map[string]any{},
+		Headers:            map[string]string{},
+		UserAgent:          DefaultUserAgent,
+		TextMode:           false,
+		LightMode:          false,
+		ExtraArgs:          []string{},
+		MaxConcurrentPages: DefaultMaxConcurrentPages,
+		MaxPagesPerContext: DefaultMaxPagesPerContext,
+		RecycleAfterPages:  0,
+		MaxBrowserRestarts: DefaultMaxBrowserRestarts,
+		DebuggingPort:      DefaultDebugPort,
+		Host:               DefaultHost,
+		EnableStealth:      false,
+		InitScripts:        []string{},
 	}
 }
@@ -3,34 +3,116 @@ package config
 const (
 	DefaultPageTimeoutMs = 60000
 	DefaultWaitUntil     = "domcontentloaded"
+	DefaultCacheMode     = CacheModeBypass
 )
 
+// Cache modes control how a run uses the crawl cache. Enabled reads and
+// writes, read-only serves hits without storing, write-only always fetches
+// and stores, and bypass skips the cache for the run. Disabled turns caching
+// off entirely.
+const (
+	CacheModeEnabled   = "enabled"
+	CacheModeDisabled  = "disabled"
+	CacheModeReadOnly  = "read_only"
+	CacheModeWriteOnly = "write_only"
+	CacheModeBypass    = "bypass"
+)
+
+// Geolocation is the position reported to pages that request it.
+type Geolocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Accuracy  float64 `json:"accuracy,omitempty"`
+}
+
 // CrawlerRunConfig controls a single crawl execution.
 // Keep this small and stable for Phase 1; extend in later phases as needed.
 type CrawlerRunConfig struct {
-	PageTimeoutMs    int    `json:"page_timeout_ms"`
-	WaitUntil        string `json:"wait_until"`
-	WaitFor          string `json:"wait_for,omitempty"`
-	WaitForTimeoutMs int    `json:"wait_for_timeout_ms,omitempty"`
-	EnableCleanHTML  bool   `json:"enable_clean_html"`
-	EnableMarkdown   bool   `json:"enable_markdown"`
-	EnableLinks      bool   `json:"enable_links"`
-	OnlyText         bool   `json:"only_text"`
-	CSSSelector      string `json:"css_selector,omitempty"`
-	Verbose          bool   `json:"verbose"`
+	PageTimeoutMs     int                   `json:"page_timeout_ms"`
+	WaitUntil         string                `json:"wait_until"`
+	WaitFor           string                `json:"wait_for,omitempty"`
+	WaitForTimeoutMs  int                   `json:"wait_for_timeout_ms,omitempty"`
+	EnableCleanHTML   bool                  `json:"enable_clean_html"`
+	EnableMarkdown    bool                  `json:"enable_markdown"`
+	EnableLinks       bool                  `json:"enable_links"`
+	OnlyText          bool                  `json:"only_text"`
+	CSSSelector       string                `json:"css_selector,omitempty"`
+	Verbose           bool                  `json:"verbose"`
+	DownloadSelector  string                `json:"download_selector,omitempty"`
+	DownloadTimeoutMs int                   `json:"download_timeout_ms,omitempty"`
+	Device            string                `json:"device,omitempty"`
+	Locale            string                `json:"locale,omitempty"`
+	TimezoneID        string                `json:"timezone_id,omitempty"`
+	Geolocation       *Geolocation          `json:"geolocation,omitempty"`
+	ColorScheme       string                `json:"color_scheme,omitempty"`
+	ProxyConfig       *ProxyConfig          `json:"proxy_config,omitempty"`
+	Headers           map[string]string     `json:"headers,omitempty"`
+	CacheMode         string                `json:"cache_mode,omitempty"`
+	CacheMaxAgeMs     int                   `json:"cache_max_age_ms,omitempty"`
+	CheckRobotsTxt    bool                  `json:"check_robots_txt"`
+	ExtractionSchema  *ExtractionSchema     `json:"extraction_schema,omitempty"`
+	EntityTypes       []string              `json:"entity_types,omitempty"`
+	EntityPatterns    map[string]string     `json:"entity_patterns,omitempty"`
+	EnableTables      bool                  `json:"enable_tables"`
+	ContentFilters    []ContentFilterConfig `json:"content_filters,omitempty"`
+	EnableCitations   bool                  `json:"enable_citations"`
+	// CustomContentFilters run after ContentFilters. They are code, so they
+	// are left out of the JSON form and of cache keys.
+	CustomContentFilters []ContentFilter `json:"-"`
 }
 
 func DefaultCrawlerRunConfig() CrawlerRunConfig {
 	return CrawlerRunConfig{
-		PageTimeoutMs:    DefaultPageTimeoutMs,
-		WaitUntil:        DefaultWaitUntil,
-		WaitFor:          "",
-		WaitForTimeoutMs: 0,
-		EnableCleanHTML:  true,
-		EnableMarkdown:   true,
-		EnableLinks:      false,
-		OnlyText:         false,
-		CSSSelector:      "",
-		Verbose:          true,
+		PageTimeoutMs:     DefaultPageTimeoutMs,
+		WaitUntil:         DefaultWaitUntil,
+		WaitFor:           "",
+		WaitForTimeoutMs:  0,
+		EnableCleanHTML:   true,
+		EnableMarkdown:    true,
+		EnableLinks:       false,
+		OnlyText:          false,
+		CSSSelector:       "",
+		Verbose:           true,
+		DownloadSelector:  "",
+		DownloadTimeoutMs: 0,
+		Device:            "",
+		Locale:            "",
+		TimezoneID:        "",
+		Geolocation:       nil,
+		ColorScheme:       "",
+		ProxyConfig:       nil,
+		Headers:           nil,
+		CacheMode:         DefaultCacheMode,
+		CacheMaxAgeMs:     0,
+		CheckRobotsTxt:    false,
+		ExtractionSchema:  nil,
+		EntityTypes:       nil,
+		EntityPatterns:    nil,
+		EnableTables:      false,
+		ContentFilters:    nil,
+		EnableCitations:   false,
 	}
 }
+
+// Content filter types for ContentFilterConfig.Type.
+const (
+	ContentFilterPruning = "pruning"
+	ContentFilterBM25    = "bm25"
+)
+
+// ContentFilter keeps the parts of an HTML page worth converting to the fit
+// Markdown and returns them as HTML.
+type ContentFilter interface {
+	Filter(html string) (string, error)
+}
+
+// ContentFilterConfig declares one filter producing the fit Markdown.
+// Threshold is the score a block needs to be kept, MinWords drops shorter
+// text blocks (pruning), and Query is what blocks must be relevant to
+// (bm25; the page title and description when empty).
+type ContentFilterConfig struct {
+	Type      string  `json:"type"`
+	Threshold float64 `json:"threshold,omitempty"`
+	MinWords  int     `json:"min_words,omitempty"`
+	Query     string  `json:"query,omitempty"`
+}
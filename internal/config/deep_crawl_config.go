@@ -0,0 +1,88 @@
+package config
+
+const (
+	DefaultDeepCrawlStrategy = DeepCrawlBFS
+	DefaultDeepCrawlMaxDepth = 2
+	DefaultDeepCrawlMaxPages = 100
+	DefaultDeepCrawlScope    = ScopeSameDomain
+)
+
+// Deep crawl strategies set the order the frontier is explored in.
+const (
+	DeepCrawlBFS       = "bfs"
+	DeepCrawlDFS       = "dfs"
+	DeepCrawlBestFirst = "best_first"
+)
+
+// Deep crawl scopes bound which hosts links may lead to, relative to the
+// seed they descend from. Same-domain ignores a leading "www.".
+const (
+	ScopeSameDomain = "same_domain"
+	ScopeSubdomain  = "subdomain"
+	ScopeAny        = "any"
+)
+
+// DeepCrawlConfig controls link-following crawls from seed URLs.
+type DeepCrawlConfig struct {
+	Strategy        string         `json:"strategy"`
+	MaxDepth        int            `json:"max_depth"`
+	MaxPages        int            `json:"max_pages"`
+	Scope           string         `json:"scope"`
+	Filters         []FilterConfig `json:"filters,omitempty"`
+	Scorers         []ScorerConfig `json:"scorers,omitempty"`
+	IgnoreRobotsTxt bool           `json:"ignore_robots_txt,omitempty"`
+}
+
+func DefaultDeepCrawlConfig() DeepCrawlConfig {
+	return DeepCrawlConfig{
+		Strategy: DefaultDeepCrawlStrategy,
+		MaxDepth: DefaultDeepCrawlMaxDepth,
+		MaxPages: DefaultDeepCrawlMaxPages,
+		Scope:    DefaultDeepCrawlScope,
+	}
+}
+
+// URL filter types for FilterConfig.Type.
+const (
+	FilterDomain         = "domain"
+	FilterGlob           = "glob"
+	FilterRegex          = "regex"
+	FilterPathPrefix     = "path_prefix"
+	FilterExtension      = "extension"
+	FilterContentType    = "content_type"
+	FilterMaxQueryParams = "max_query_params"
+)
+
+// FilterConfig declares one deep-crawl URL filter. Allow and Deny list
+// domains, extensions or content types; Include and Exclude list glob or
+// regex patterns or path prefixes; Max bounds query parameters.
+type FilterConfig struct {
+	Type    string   `json:"type"`
+	Allow   []string `json:"allow,omitempty"`
+	Deny    []string `json:"deny,omitempty"`
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+	Max     int      `json:"max,omitempty"`
+}
+
+// URL scorer types for ScorerConfig.Type.
+const (
+	ScorerKeyword         = "keyword"
+	ScorerPathDepth       = "path_depth"
+	ScorerFreshness       = "freshness"
+	ScorerDomainAuthority = "domain_authority"
+	ScorerContentType     = "content_type"
+)
+
+// ScorerConfig declares one best-first URL scorer and its weight in the
+// combined score. Weights maps domains or content-type prefixes to scores
+// for the domain_authority and content_type scorers.
+type ScorerConfig struct {
+	Type         string             `json:"type"`
+	Weight       float64            `json:"weight,omitempty"`
+	Keywords     []string           `json:"keywords,omitempty"`
+	OptimalDepth int                `json:"optimal_depth,omitempty"`
+	CurrentYear  int                `json:"current_year,omitempty"`
+	Weights      map[string]float64 `json:"weights,omitempty"`
+	Default      float64            `json:"default,omitempty"`
+}
@@ -0,0 +1,41 @@
+package config
+
+// Selector types for ExtractionSchema and SchemaField.
+const (
+	SelectorTypeCSS   = "css"
+	SelectorTypeXPath = "xpath"
+)
+
+// Schema field types. Text, attribute, html and regex read one value from
+// the first element the selector matches. Nested builds an object from the
+// sub-fields of the first match, and list returns one value per match: an
+// object built from the sub-fields, or the element's text or attribute when
+// there are none.
+const (
+	FieldTypeText      = "text"
+	FieldTypeAttribute = "attribute"
+	FieldTypeHTML      = "html"
+	FieldTypeRegex     = "regex"
+	FieldTypeNested    = "nested"
+	FieldTypeList      = "list"
+)
+
+// ExtractionSchema describes structured data to scrape from a page: one
+// object per element matching BaseSelector, built from Fields.
+type ExtractionSchema struct {
+	Name         string        `json:"name,omitempty"`
+	BaseSelector string        `json:"base_selector"`
+	SelectorType string        `json:"selector_type,omitempty"`
+	Fields       []SchemaField `json:"fields"`
+}
+
+type SchemaField struct {
+	Name         string        `json:"name"`
+	Selector     string        `json:"selector,omitempty"`
+	SelectorType string        `json:"selector_type,omitempty"`
+	Type         string        `json:"type,omitempty"`
+	Attribute    string        `json:"attribute,omitempty"`
+	Pattern      string        `json:"pattern,omitempty"`
+	Fields       []SchemaField `json:"fields,omitempty"`
+	Default      any           `json:"default,omitempty"`
+}
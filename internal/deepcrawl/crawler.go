@@ -0,0 +1,345 @@
+package deepcrawl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/model"
+	"github.com/techbysteve/prowl4ai/internal/prowler"
+	"github.com/techbysteve/prowl4ai/internal/urlutil"
+)
+
+// Candidate is a discovered link considered for the frontier.
+type Candidate struct {
+	URL        string `json:"url"`
+	AnchorText string `json:"anchor_text,omitempty"`
+	Depth      int    `json:"depth"`
+	ParentURL  string `json:"parent_url,omitempty"`
+}
+
+// URLScorer rates candidates for best-first crawls; higher scores are
+// crawled first.
+type URLScorer interface {
+	Score(c Candidate) float64
+}
+
+// Options carries the deep crawl hooks that cannot be declared in config.
+type Options struct {
+	// Scorer ranks links for best-first crawls. It is combined with the
+	// scorers declared in config; with neither, shallow paths go first.
+	Scorer URLScorer
+	// Filters run after the filters declared in config.
+	Filters []URLFilter
+	// OnReject is called for every link kept out of the frontier.
+	OnReject func(Rejection)
+	// StatePath, when set, is where the crawl state is checkpointed. A
+	// checkpoint is always written when the crawl stops, including on
+	// cancellation.
+	StatePath string
+	// CheckpointInterval is the minimum time between checkpoints while the
+	// crawl runs; zero checkpoints after every page.
+	CheckpointInterval time.Duration
+}
+
+// Crawler follows links from seed URLs, fetching each page through a
+// prowler.Service.
+type Crawler struct {
+	service *prowler.Service
+	cfg     config.DeepCrawlConfig
+	opts    Options
+}
+
+// New returns a deep crawler.
+func New(service *prowler.Service, cfg config.DeepCrawlConfig, opts Options) *Crawler {
+	return &Crawler{service: service, cfg: cfg, opts: opts}
+}
+
+// Run crawls from seeds and calls emit with every page in crawl order. Pages
+// that fail are emitted with their error and not expanded. Links outside the
+// scope or rejected by a filter never enter the frontier; seeds are not
+// filtered. A negative MaxDepth follows links without a depth limit and a
+// non-positive MaxPages crawls until the frontier is empty. Pages robots.txt
+// disallows are emitted as failures unless IgnoreRobotsTxt is set. Run stops
+// early if emit returns an error.
+func (c *Crawler) Run(ctx context.Context, seeds []string, runCfg config.CrawlerRunConfig, emit func(model.CrawlResult) error) error {
+	r, err := c.newRun(c.cfg)
+	if err != nil {
+		return err
+	}
+	for _, seed := range seeds {
+		r.enqueue(Candidate{URL: seed}, hostOf(seed))
+	}
+	return r.loop(ctx, runCfg, emit)
+}
+
+// Resume continues the crawl saved in state with the saved config in place of
+// the crawler's own. Pages crawled before the checkpoint are not fetched
+// again; pages that were in flight are fetched first.
+func (c *Crawler) Resume(ctx context.Context, state *State, runCfg config.CrawlerRunConfig, emit func(model.CrawlResult) error) error {
+	r, err := c.newRun(state.Config)
+	if err != nil {
+		return err
+	}
+	for _, key := range state.Visited {
+		r.visited[key] = true
+	}
+	r.crawled = state.Crawled
+	r.seq = state.Seq
+
+	// In-flight URLs were popped last, so they go back where pop takes from:
+	// the head of a queue, the top of a stack. Heap order comes from seq.
+	inFlight := make([]item, 0, len(state.InFlight))
+	for _, q := range state.InFlight {
+		inFlight = append(inFlight, q.item())
+	}
+	queued := make([]item, 0, len(state.Frontier))
+	for _, q := range state.Frontier {
+		queued = append(queued, q.item())
+	}
+	order := append(inFlight, queued...)
+	if r.cfg.Strategy == config.DeepCrawlDFS {
+		order = append(queued, inFlight...)
+	}
+	for _, it := range order {
+		r.frontier.push(it)
+	}
+	return r.loop(ctx, runCfg, emit)
+}
+
+func (c *Crawler) newRun(cfg config.DeepCrawlConfig) (*run, error) {
+	if cfg.Strategy == "" {
+		cfg.Strategy = config.DefaultDeepCrawlStrategy
+	}
+	switch cfg.Strategy {
+	case config.DeepCrawlBFS, config.DeepCrawlDFS, config.DeepCrawlBestFirst:
+	default:
+		return nil, fmt.Errorf("unsupported deep crawl strategy: %s", cfg.Strategy)
+	}
+	if cfg.Scope == "" {
+		cfg.Scope = config.DefaultDeepCrawlScope
+	}
+	switch cfg.Scope {
+	case config.ScopeSameDomain, config.ScopeSubdomain, config.ScopeAny:
+	default:
+		return nil, fmt.Errorf("unsupported deep crawl scope: %s", cfg.Scope)
+	}
+	filters, err := BuildFilters(cfg.Filters)
+	if err != nil {
+		return nil, err
+	}
+	filters = append(filters, c.opts.Filters...)
+	scorer, err := c.scorer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &run{
+		crawler:  c,
+		cfg:      cfg,
+		filters:  filters,
+		scorer:   scorer,
+		frontier: newFrontier(cfg.Strategy),
+		visited:  map[string]bool{},
+		inFlight: map[int]item{},
+	}, nil
+}
+
+// loop crawls the frontier until it is empty, the page cap is reached or the
+// crawl is stopped, then writes a final checkpoint.
+func (r *run) loop(ctx context.Context, runCfg config.CrawlerRunConfig, emit func(model.CrawlResult) error) error {
+	err := r.crawl(ctx, runCfg, emit)
+	if r.crawler.opts.StatePath != "" {
+		if saveErr := r.checkpoint(); saveErr != nil {
+			return errors.Join(err, saveErr)
+		}
+	}
+	return err
+}
+
+func (r *run) crawl(ctx context.Context, runCfg config.CrawlerRunConfig, emit func(model.CrawlResult) error) error {
+	runCfg.EnableLinks = true
+	runCfg.CheckRobotsTxt = !r.cfg.IgnoreRobotsTxt
+	opts := r.crawler.opts
+	lastCheckpoint := time.Now()
+	for r.frontier.len() > 0 {
+		if r.cfg.MaxPages > 0 && r.crawled >= r.cfg.MaxPages {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		it := r.frontier.pop()
+		r.inFlight[it.seq] = it
+		result, _ := r.crawler.service.Run(ctx, it.URL, runCfg)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		result.Depth = it.Depth
+		result.ParentURL = it.ParentURL
+		if err := emit(result); err != nil {
+			return err
+		}
+		delete(r.inFlight, it.seq)
+		r.crawled++
+
+		if result.Success && (r.cfg.MaxDepth < 0 || it.Depth < r.cfg.MaxDepth) {
+			seedHost := it.seedHost
+			if it.Depth == 0 && result.RedirectedURL != "" {
+				// Follow the seed's own redirect, e.g. example.com to docs.example.com.
+				seedHost = hostOf(result.RedirectedURL)
+			}
+			r.expand(result, it, seedHost)
+		}
+
+		if opts.StatePath != "" && time.Since(lastCheckpoint) >= opts.CheckpointInterval {
+			if err := r.checkpoint(); err != nil {
+				return err
+			}
+			lastCheckpoint = time.Now()
+		}
+	}
+	return nil
+}
+
+// checkpoint saves the crawl state to Options.StatePath.
+func (r *run) checkpoint() error {
+	state := &State{
+		Version:   stateVersion,
+		Config:    r.cfg,
+		Frontier:  []QueuedURL{},
+		Visited:   make([]string, 0, len(r.visited)),
+		Crawled:   r.crawled,
+		Seq:       r.seq,
+		UpdatedAt: time.Now().UTC(),
+	}
+	for _, it := range r.frontier.items() {
+		state.Frontier = append(state.Frontier, queuedURL(it))
+	}
+	for _, it := range r.inFlight {
+		state.InFlight = append(state.InFlight, queuedURL(it))
+	}
+	for key := range r.visited {
+		state.Visited = append(state.Visited, key)
+	}
+	slices.Sort(state.Visited)
+	return state.Save(r.crawler.opts.StatePath)
+}
+
+// scorer combines the declared scorers with Options.Scorer at weight 1.
+func (c *Crawler) scorer(cfg config.DeepCrawlConfig) (URLScorer, error) {
+	composite, err := BuildScorer(cfg.Scorers)
+	if err != nil {
+		return nil, err
+	}
+	if c.opts.Scorer != nil {
+		composite = append(composite, WeightedScorer{Scorer: c.opts.Scorer, Weight: 1})
+	}
+	if len(composite) == 0 {
+		return PathDepthScorer{}, nil
+	}
+	return composite, nil
+}
+
+// run is the state of one Crawler.Run or Crawler.Resume call.
+type run struct {
+	crawler  *Crawler
+	cfg      config.DeepCrawlConfig
+	filters  FilterChain
+	scorer   URLScorer
+	frontier frontier
+	visited  map[string]bool
+	// inFlight holds popped items whose page has not been emitted yet, by seq.
+	inFlight map[int]item
+	crawled  int
+	seq      int
+}
+
+func (r *run) expand(result model.CrawlResult, parent item, seedHost string) {
+	links := append(slices.Clone(result.Links.Internal), result.Links.External...)
+	if r.cfg.Strategy == config.DeepCrawlDFS {
+		// Push in reverse so the page's first link is explored first.
+		slices.Reverse(links)
+	}
+	for _, link := range links {
+		c := Candidate{
+			URL:        link.Href,
+			AnchorText: link.Text,
+			Depth:      parent.Depth + 1,
+			ParentURL:  parent.URL,
+		}
+		if r.seen(c.URL) {
+			continue
+		}
+		if host := hostOf(c.URL); !inScope(host, seedHost, r.cfg.Scope) {
+			r.reject(c, fmt.Sprintf("host %s is outside the %s scope of %s", host, r.cfg.Scope, seedHost))
+			continue
+		}
+		if err := r.filters.Check(c); err != nil {
+			r.reject(c, err.Error())
+			continue
+		}
+		r.enqueue(c, seedHost)
+	}
+}
+
+// seen reports whether an equivalent URL was already queued or rejected.
+func (r *run) seen(rawURL string) bool {
+	return r.visited[visitKey(rawURL)]
+}
+
+func (r *run) reject(c Candidate, reason string) {
+	r.visited[visitKey(c.URL)] = true
+	if r.crawler.opts.OnReject != nil {
+		r.crawler.opts.OnReject(Rejection{URL: c.URL, ParentURL: c.ParentURL, Depth: c.Depth, Reason: reason})
+	}
+}
+
+func visitKey(rawURL string) string {
+	key, err := urlutil.Normalize(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return key
+}
+
+// enqueue adds c to the frontier unless an equivalent URL was seen before.
+func (r *run) enqueue(c Candidate, seedHost string) {
+	key := visitKey(c.URL)
+	if r.visited[key] {
+		return
+	}
+	r.visited[key] = true
+
+	it := item{Candidate: c, seedHost: seedHost, seq: r.seq}
+	r.seq++
+	if r.cfg.Strategy == config.DeepCrawlBestFirst {
+		it.score = r.scorer.Score(c)
+	}
+	r.frontier.push(it)
+}
+
+func inScope(host, seedHost, scope string) bool {
+	switch scope {
+	case config.ScopeAny:
+		return true
+	case config.ScopeSubdomain:
+		return host == seedHost || strings.HasSuffix(host, "."+seedHost)
+	default:
+		return host == seedHost
+	}
+}
+
+// hostOf returns the lower-cased host of rawURL without a leading "www.".
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
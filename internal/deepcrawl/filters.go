@@ -0,0 +1,315 @@
+package deepcrawl
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+)
+
+// URLFilter decides whether a discovered link may enter the frontier. Check
+// returns nil to accept the candidate or an error stating why it is rejected.
+type URLFilter interface {
+	Check(c Candidate) error
+}
+
+// Rejection records a link a filter or the crawl scope kept out of the frontier.
+type Rejection struct {
+	URL       string `json:"url"`
+	ParentURL string `json:"parent_url,omitempty"`
+	Depth     int    `json:"depth"`
+	Reason    string `json:"reason"`
+}
+
+// FilterChain applies filters in order; the first rejection wins.
+type FilterChain []URLFilter
+
+func (fc FilterChain) Check(c Candidate) error {
+	for _, f := range fc {
+		if err := f.Check(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DomainFilter accepts hosts in Allowed (when set) and rejects hosts in
+// Blocked. Entries match the domain and its subdomains.
+type DomainFilter struct {
+	Allowed []string
+	Blocked []string
+}
+
+func (f DomainFilter) Check(c Candidate) error {
+	host := hostOf(c.URL)
+	for _, d := range f.Blocked {
+		if domainMatches(host, d) {
+			return fmt.Errorf("domain %s is blocked", host)
+		}
+	}
+	if len(f.Allowed) == 0 {
+		return nil
+	}
+	for _, d := range f.Allowed {
+		if domainMatches(host, d) {
+			return nil
+		}
+	}
+	return fmt.Errorf("domain %s is not allowed", host)
+}
+
+func domainMatches(host, domain string) bool {
+	domain = strings.TrimPrefix(strings.ToLower(domain), "www.")
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// PatternFilter matches the full URL against regular expressions. A URL must
+// match one Include pattern, when any are set, and no Exclude pattern.
+type PatternFilter struct {
+	Include []*regexp.Regexp
+	Exclude []*regexp.Regexp
+}
+
+func (f PatternFilter) Check(c Candidate) error {
+	for _, re := range f.Exclude {
+		if re.MatchString(c.URL) {
+			return fmt.Errorf("url matches excluded pattern %s", re)
+		}
+	}
+	if len(f.Include) == 0 {
+		return nil
+	}
+	for _, re := range f.Include {
+		if re.MatchString(c.URL) {
+			return nil
+		}
+	}
+	return fmt.Errorf("url matches no included pattern")
+}
+
+// NewGlobFilter builds a PatternFilter from glob patterns matched against the
+// whole URL, where "*" matches any run of characters and "?" a single one.
+func NewGlobFilter(include, exclude []string) PatternFilter {
+	return PatternFilter{Include: globs(include), Exclude: globs(exclude)}
+}
+
+// NewRegexFilter builds a PatternFilter from regular expressions, which match
+// anywhere in the URL unless anchored.
+func NewRegexFilter(include, exclude []string) (PatternFilter, error) {
+	in, err := compileAll(include)
+	if err != nil {
+		return PatternFilter{}, err
+	}
+	ex, err := compileAll(exclude)
+	if err != nil {
+		return PatternFilter{}, err
+	}
+	return PatternFilter{Include: in, Exclude: ex}, nil
+}
+
+func globs(patterns []string) []*regexp.Regexp {
+	out := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		var sb strings.Builder
+		sb.WriteString("^")
+		for _, r := range p {
+			switch r {
+			case '*':
+				sb.WriteString(".*")
+			case '?':
+				sb.WriteString(".")
+			default:
+				sb.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		sb.WriteString("$")
+		out = append(out, regexp.MustCompile(sb.String()))
+	}
+	return out
+}
+
+func compileAll(patterns []string) ([]*regexp.Regexp, error) {
+	out := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, re)
+	}
+	return out, nil
+}
+
+// PathPrefixFilter accepts URL paths starting with one of Include, when set,
+// and rejects paths starting with one of Exclude.
+type PathPrefixFilter struct {
+	Include []string
+	Exclude []string
+}
+
+func (f PathPrefixFilter) Check(c Candidate) error {
+	p := urlPath(c.URL)
+	for _, prefix := range f.Exclude {
+		if strings.HasPrefix(p, prefix) {
+			return fmt.Errorf("path %s has excluded prefix %s", p, prefix)
+		}
+	}
+	if len(f.Include) == 0 {
+		return nil
+	}
+	for _, prefix := range f.Include {
+		if strings.HasPrefix(p, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("path %s has no included prefix", p)
+}
+
+// ExtensionFilter rejects paths whose file extension is in Blocked or, when
+// Allowed is set, not in Allowed. Paths without an extension are accepted.
+type ExtensionFilter struct {
+	Allowed []string
+	Blocked []string
+}
+
+func (f ExtensionFilter) Check(c Candidate) error {
+	ext := strings.ToLower(path.Ext(urlPath(c.URL)))
+	if ext == "" {
+		return nil
+	}
+	if slices.ContainsFunc(f.Blocked, func(e string) bool { return normalizeExt(e) == ext }) {
+		return fmt.Errorf("extension %s is blocked", ext)
+	}
+	if len(f.Allowed) > 0 && !slices.ContainsFunc(f.Allowed, func(e string) bool { return normalizeExt(e) == ext }) {
+		return fmt.Errorf("extension %s is not allowed", ext)
+	}
+	return nil
+}
+
+func normalizeExt(ext string) string {
+	ext = strings.ToLower(ext)
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
+// ContentTypeFilter accepts URLs whose content type, guessed from the file
+// extension, starts with one of Allowed (e.g. "text/html" or "text/"). Paths
+// without an extension are assumed to be HTML.
+type ContentTypeFilter struct {
+	Allowed []string
+}
+
+func (f ContentTypeFilter) Check(c Candidate) error {
+	if len(f.Allowed) == 0 {
+		return nil
+	}
+	contentType := "text/html"
+	if ext := path.Ext(urlPath(c.URL)); ext != "" {
+		guessed, _, _ := strings.Cut(mime.TypeByExtension(strings.ToLower(ext)), ";")
+		if guessed == "" {
+			return fmt.Errorf("unknown content type for extension %s", ext)
+		}
+		contentType = guessed
+	}
+	for _, allowed := range f.Allowed {
+		if strings.HasPrefix(contentType, strings.ToLower(allowed)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("content type %s is not allowed", contentType)
+}
+
+// MaxQueryParamsFilter rejects URLs with more than Max query parameters,
+// which usually signal faceted or session-tracking URL spaces.
+type MaxQueryParamsFilter struct {
+	Max int
+}
+
+func (f MaxQueryParamsFilter) Check(c Candidate) error {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return err
+	}
+	n := 0
+	for _, part := range strings.Split(u.RawQuery, "&") {
+		if part != "" {
+			n++
+		}
+	}
+	if n > f.Max {
+		return fmt.Errorf("%d query parameters exceed the limit of %d", n, f.Max)
+	}
+	return nil
+}
+
+func urlPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
+// BuildFilters turns declarative filter specs into a chain.
+func BuildFilters(specs []config.FilterConfig) (FilterChain, error) {
+	chain := make(FilterChain, 0, len(specs))
+	for i, spec := range specs {
+		var f URLFilter
+		switch spec.Type {
+		case config.FilterDomain:
+			f = DomainFilter{Allowed: spec.Allow, Blocked: spec.Deny}
+		case config.FilterGlob:
+			f = NewGlobFilter(spec.Include, spec.Exclude)
+		case config.FilterRegex:
+			rf, err := NewRegexFilter(spec.Include, spec.Exclude)
+			if err != nil {
+				return nil, fmt.Errorf("filter %d: %w", i, err)
+			}
+			f = rf
+		case config.FilterPathPrefix:
+			f = PathPrefixFilter{Include: spec.Include, Exclude: spec.Exclude}
+		case config.FilterExtension:
+			f = ExtensionFilter{Allowed: spec.Allow, Blocked: spec.Deny}
+		case config.FilterContentType:
+			f = ContentTypeFilter{Allowed: spec.Allow}
+		case config.FilterMaxQueryParams:
+			f = MaxQueryParamsFilter{Max: spec.Max}
+		default:
+			return nil, fmt.Errorf("filter %d: unsupported type %q", i, spec.Type)
+		}
+		chain = append(chain, f)
+	}
+	return chain, nil
+}
+
+// LoadFilters reads a JSON array of filter specs from path and builds them.
+func LoadFilters(path string) (FilterChain, error) {
+	specs, err := ReadFilterSpecs(path)
+	if err != nil {
+		return nil, err
+	}
+	return BuildFilters(specs)
+}
+
+// ReadFilterSpecs reads a JSON array of filter specs from path, as kept in
+// DeepCrawlConfig.Filters.
+func ReadFilterSpecs(path string) ([]config.FilterConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var specs []config.FilterConfig
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return specs, nil
+}
@@ -0,0 +1,111 @@
+package deepcrawl
+
+import (
+	"container/heap"
+	"slices"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+)
+
+// item is a URL waiting in the frontier.
+type item struct {
+	Candidate
+	seedHost string
+	score    float64
+	seq      int
+}
+
+// frontier holds discovered URLs in the order a strategy explores them.
+type frontier interface {
+	push(it item)
+	pop() item
+	len() int
+	// items lists the queued items without removing them.
+	items() []item
+}
+
+func newFrontier(strategy string) frontier {
+	switch strategy {
+	case config.DeepCrawlDFS:
+		return &stack{}
+	case config.DeepCrawlBestFirst:
+		return &priorityQueue{}
+	default:
+		return &queue{}
+	}
+}
+
+// queue explores breadth-first.
+type queue struct {
+	queued []item
+	head   int
+}
+
+func (q *queue) push(it item) { q.queued = append(q.queued, it) }
+
+func (q *queue) pop() item {
+	it := q.queued[q.head]
+	q.queued[q.head] = item{}
+	q.head++
+	if q.head > len(q.queued)/2 {
+		q.queued = append(q.queued[:0], q.queued[q.head:]...)
+		q.head = 0
+	}
+	return it
+}
+
+func (q *queue) len() int { return len(q.queued) - q.head }
+
+func (q *queue) items() []item { return slices.Clone(q.queued[q.head:]) }
+
+// stack explores depth-first.
+type stack struct {
+	queued []item
+}
+
+func (s *stack) push(it item) { s.queued = append(s.queued, it) }
+
+func (s *stack) pop() item {
+	it := s.queued[len(s.queued)-1]
+	s.queued = s.queued[:len(s.queued)-1]
+	return it
+}
+
+func (s *stack) len() int { return len(s.queued) }
+
+func (s *stack) items() []item { return slices.Clone(s.queued) }
+
+// priorityQueue explores the highest-scored URL first, oldest first on ties.
+type priorityQueue struct {
+	h itemHeap
+}
+
+func (p *priorityQueue) push(it item) { heap.Push(&p.h, it) }
+
+func (p *priorityQueue) pop() item { return heap.Pop(&p.h).(item) }
+
+func (p *priorityQueue) len() int { return p.h.Len() }
+
+func (p *priorityQueue) items() []item { return slices.Clone(p.h) }
+
+type itemHeap []item
+
+func (h itemHeap) Len() int { return len(h) }
+
+func (h itemHeap) Less(i, j int) bool {
+	if h[i].score != h[j].score {
+		return h[i].score > h[j].score
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h itemHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *itemHeap) Push(x any) { *h = append(*h, x.(item)) }
+
+func (h *itemHeap) Pop() any {
+	old := *h
+	it := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return it
+}
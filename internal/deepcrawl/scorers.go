@@ -0,0 +1,191 @@
+package deepcrawl
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+)
+
+// KeywordScorer scores the share of Keywords found in the URL or anchor text.
+type KeywordScorer struct {
+	Keywords []string
+}
+
+func (s KeywordScorer) Score(c Candidate) float64 {
+	if len(s.Keywords) == 0 {
+		return 0
+	}
+	text := strings.ToLower(c.URL + " " + c.AnchorText)
+	hits := 0
+	for _, kw := range s.Keywords {
+		if kw != "" && strings.Contains(text, strings.ToLower(kw)) {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(s.Keywords))
+}
+
+// PathDepthScorer prefers URLs whose path has Optimal segments, falling off
+// as 1/(1+distance). With Optimal zero, shallower paths always score higher.
+type PathDepthScorer struct {
+	Optimal int
+}
+
+func (s PathDepthScorer) Score(c Candidate) float64 {
+	segments := len(strings.FieldsFunc(urlPath(c.URL), func(r rune) bool { return r == '/' }))
+	distance := segments - s.Optimal
+	if distance < 0 {
+		distance = -distance
+	}
+	return 1 / float64(1+distance)
+}
+
+// yearPattern finds years between 1990 and 2099 delimited by non-digits.
+var yearPattern = regexp.MustCompile(`(?:^|\D)(199\d|20\d\d)(?:\D|$)`)
+
+// FreshnessScorer rewards URLs carrying a recent year, as in /2025/06/post or
+// release-2024.html: the current year scores 1, losing 0.2 per year of age.
+// URLs without a year get the neutral score 0.5.
+type FreshnessScorer struct {
+	// CurrentYear defaults to the current calendar year.
+	CurrentYear int
+}
+
+func (s FreshnessScorer) Score(c Candidate) float64 {
+	current := s.CurrentYear
+	if current == 0 {
+		current = time.Now().Year()
+	}
+	latest := 0
+	for _, m := range yearPattern.FindAllStringSubmatch(c.URL, -1) {
+		if year, err := strconv.Atoi(m[1]); err == nil && year <= current && year > latest {
+			latest = year
+		}
+	}
+	if latest == 0 {
+		return 0.5
+	}
+	return max(0, 1-0.2*float64(current-latest))
+}
+
+// DomainAuthorityScorer scores hosts by Weights, keyed by domain and matching
+// subdomains; the most specific domain wins. Other hosts get Default.
+type DomainAuthorityScorer struct {
+	Weights map[string]float64
+	Default float64
+}
+
+func (s DomainAuthorityScorer) Score(c Candidate) float64 {
+	host := hostOf(c.URL)
+	best, bestLen := s.Default, -1
+	for domain, weight := range s.Weights {
+		if domainMatches(host, domain) && len(domain) > bestLen {
+			best, bestLen = weight, len(domain)
+		}
+	}
+	return best
+}
+
+// ContentTypeScorer scores URLs by the content type guessed from their file
+// extension, keyed by type prefix such as "text/html" or "application/";
+// the longest matching prefix wins. Paths without an extension count as
+// text/html and unknown types get Default.
+type ContentTypeScorer struct {
+	Weights map[string]float64
+	Default float64
+}
+
+func (s ContentTypeScorer) Score(c Candidate) float64 {
+	contentType := "text/html"
+	if ext := path.Ext(urlPath(c.URL)); ext != "" {
+		contentType, _, _ = strings.Cut(mime.TypeByExtension(strings.ToLower(ext)), ";")
+	}
+	best, bestLen := s.Default, -1
+	for prefix, weight := range s.Weights {
+		if contentType != "" && strings.HasPrefix(contentType, strings.ToLower(prefix)) && len(prefix) > bestLen {
+			best, bestLen = weight, len(prefix)
+		}
+	}
+	return best
+}
+
+// WeightedScorer is one part of a CompositeScorer.
+type WeightedScorer struct {
+	Scorer URLScorer
+	Weight float64
+}
+
+// CompositeScorer combines scorers into their weighted average.
+type CompositeScorer []WeightedScorer
+
+func (cs CompositeScorer) Score(c Candidate) float64 {
+	var sum, total float64
+	for _, ws := range cs {
+		sum += ws.Weight * ws.Scorer.Score(c)
+		total += ws.Weight
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// BuildScorer turns declarative scorer specs into a CompositeScorer. A spec
+// without a weight counts with weight 1.
+func BuildScorer(specs []config.ScorerConfig) (CompositeScorer, error) {
+	composite := make(CompositeScorer, 0, len(specs))
+	for i, spec := range specs {
+		var s URLScorer
+		switch spec.Type {
+		case config.ScorerKeyword:
+			s = KeywordScorer{Keywords: spec.Keywords}
+		case config.ScorerPathDepth:
+			s = PathDepthScorer{Optimal: spec.OptimalDepth}
+		case config.ScorerFreshness:
+			s = FreshnessScorer{CurrentYear: spec.CurrentYear}
+		case config.ScorerDomainAuthority:
+			s = DomainAuthorityScorer{Weights: spec.Weights, Default: spec.Default}
+		case config.ScorerContentType:
+			s = ContentTypeScorer{Weights: spec.Weights, Default: spec.Default}
+		default:
+			return nil, fmt.Errorf("scorer %d: unsupported type %q", i, spec.Type)
+		}
+		weight := spec.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		composite = append(composite, WeightedScorer{Scorer: s, Weight: weight})
+	}
+	return composite, nil
+}
+
+// LoadScorer reads a JSON array of scorer specs from path and builds them.
+func LoadScorer(path string) (CompositeScorer, error) {
+	specs, err := ReadScorerSpecs(path)
+	if err != nil {
+		return nil, err
+	}
+	return BuildScorer(specs)
+}
+
+// ReadScorerSpecs reads a JSON array of scorer specs from path, as kept in
+// DeepCrawlConfig.Scorers.
+func ReadScorerSpecs(path string) ([]config.ScorerConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var specs []config.ScorerConfig
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return specs, nil
+}
@@ -0,0 +1,74 @@
+package deepcrawl
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/fsutil"
+)
+
+// stateVersion is bumped when the State file layout changes incompatibly.
+const stateVersion = 1
+
+// State is a checkpoint of a deep crawl: everything needed to continue it
+// without re-fetching the pages it already crawled.
+type State struct {
+	Version int                    `json:"version"`
+	Config  config.DeepCrawlConfig `json:"config"`
+	// Frontier holds the queued URLs and InFlight the URLs being fetched when
+	// the checkpoint was taken; in-flight URLs are re-queued on resume.
+	Frontier  []QueuedURL `json:"frontier"`
+	InFlight  []QueuedURL `json:"in_flight,omitempty"`
+	Visited   []string    `json:"visited"`
+	Crawled   int         `json:"crawled"`
+	Seq       int         `json:"seq"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// QueuedURL is a frontier entry as stored in a State.
+type QueuedURL struct {
+	Candidate
+	SeedHost string  `json:"seed_host"`
+	Score    float64 `json:"score,omitempty"`
+	Seq      int     `json:"seq"`
+}
+
+// Save writes the state to path atomically, so a crash mid-write leaves the
+// previous checkpoint intact.
+func (s *State) Save(path string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("encode crawl state: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("write crawl state: %w", err)
+	}
+	return nil
+}
+
+// LoadState reads a state saved by State.Save.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read crawl state: %w", err)
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse crawl state %s: %w", path, err)
+	}
+	if s.Version != stateVersion {
+		return nil, fmt.Errorf("crawl state %s has unsupported version %d", path, s.Version)
+	}
+	return &s, nil
+}
+
+func queuedURL(it item) QueuedURL {
+	return QueuedURL{Candidate: it.Candidate, SeedHost: it.seedHost, Score: it.score, Seq: it.seq}
+}
+
+func (q QueuedURL) item() item {
+	return item{Candidate: q.Candidate, seedHost: q.SeedHost, score: q.Score, seq: q.Seq}
+}
@@ -0,0 +1,129 @@
+package extract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/mediatype"
+	"github.com/techbysteve/prowl4ai/internal/stderrors"
+	"golang.org/x/net/html/charset"
+)
+
+// ProcessBody dispatches a raw response body to the extractor matching its
+// content type. HTML bodies are handed to Process unchanged.
+func ProcessBody(body []byte, contentType, baseURL string, cfg config.CrawlerRunConfig) (Output, error) {
+	mediaType := mediatype.Of(contentType)
+	var (
+		out Output
+		err error
+	)
+	switch {
+	case mediatype.IsHTML(mediaType):
+		text, err := decodeText(body, contentType)
+		if err != nil {
+			return Output{}, err
+		}
+		return Process(text, baseURL, cfg)
+	case mediaType == "application/pdf":
+		out, err = processPDF(body)
+	case isJSON(mediaType):
+		out, err = processJSON(body, contentType)
+	case isXML(mediaType), strings.HasPrefix(mediaType, "text/"):
+		out, err = processText(body, contentType)
+	default:
+		return Output{}, fmt.Errorf("%w: %s", stderrors.ErrUnsupportedContentType, mediaType)
+	}
+	if err != nil {
+		return out, err
+	}
+	return withEntities(out, out.Markdown, cfg)
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func isXML(mediaType string) bool {
+	return mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml")
+}
+
+func decodeText(body []byte, contentType string) (string, error) {
+	reader, err := charset.NewReader(bytes.NewReader(body), contentType)
+	if err != nil {
+		return "", err
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+func processText(body []byte, contentType string) (Output, error) {
+	text, err := decodeText(body, contentType)
+	if err != nil {
+		return Output{}, err
+	}
+	return Output{
+		Markdown: text,
+		Metadata: map[string]any{},
+	}, nil
+}
+
+func processJSON(body []byte, contentType string) (Output, error) {
+	text, err := decodeText(body, contentType)
+	if err != nil {
+		return Output{}, err
+	}
+	// Malformed JSON is still text worth keeping, so return it as served.
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, []byte(text), "", "  "); err == nil {
+		text = pretty.String()
+	}
+	return Output{
+		Markdown: text,
+		Metadata: map[string]any{},
+	}, nil
+}
+
+func processPDF(body []byte) (out Output, err error) {
+	// The PDF parser panics on some malformed inputs; surface those as errors.
+	defer func() {
+		if r := recover(); r != nil {
+			out = Output{}
+			err = fmt.Errorf("parse pdf: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return Output{}, err
+	}
+
+	pages := make([]string, 0, reader.NumPage())
+	for i := 1; i <= reader.NumPage(); i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		text, err := page.GetPlainText(nil)
+		if err != nil {
+			return Output{}, err
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			pages = append(pages, text)
+		}
+	}
+
+	return Output{
+		Markdown: strings.Join(pages, "\n\n"),
+		Metadata: map[string]any{
+			"pages": reader.NumPage(),
+		},
+	}, nil
+}
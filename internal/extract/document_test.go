@@ -0,0 +1,27 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+)
+
+func TestProcessBodyJSON(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+	}{
+		{"valid", `{"a":[1,2]}`, "{\n  \"a\": [\n    1,\n    2\n  ]\n}"},
+		{"malformed", `{"a": [1, 2`, `{"a": [1, 2`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ProcessBody([]byte(tt.body), "application/json", "https://example.com/", config.DefaultCrawlerRunConfig())
+			if err != nil {
+				t.Fatalf("ProcessBody: %v", err)
+			}
+			if string(out.Markdown) != tt.want {
+				t.Errorf("Markdown = %q, want %q", out.Markdown, tt.want)
+			}
+		})
+	}
+}
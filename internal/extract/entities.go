@@ -0,0 +1,293 @@
+package extract
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/model"
+	"golang.org/x/net/html"
+)
+
+// EntityTypeAll in CrawlerRunConfig.EntityTypes enables every built-in type.
+const EntityTypeAll = "all"
+
+type entityPattern struct {
+	name string
+	re   *regexp.Regexp
+	// valid rejects matches the pattern cannot rule out on its own.
+	valid func(string) bool
+	// standalone rejects matches touching a word character or colon, a
+	// boundary Go regexps cannot assert themselves.
+	standalone bool
+}
+
+const monthNames = `(?:Jan(?:uary)?|Feb(?:ruary)?|Mar(?:ch)?|Apr(?:il)?|May|June?|July?|Aug(?:ust)?|Sep(?:t(?:ember)?)?|Oct(?:ober)?|Nov(?:ember)?|Dec(?:ember)?)`
+
+const currencyCodes = `(?:USD|EUR|GBP|JPY|CHF|CAD|AUD|NZD|CNY|INR|SEK|NOK|DKK|PLN|BRL|MXN)`
+
+// builtinEntities are matched in order; a match overlapping one of an
+// earlier type is dropped, so a UUID is not also read as a phone number.
+var builtinEntities = []entityPattern{
+	{name: model.EntityURL, re: regexp.MustCompile(`\bhttps?://[^\s<>"'()\[\]{}]+`)},
+	{name: model.EntityEmail, re: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}\b`)},
+	{name: model.EntityUUID, re: regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`)},
+	{name: model.EntityIPv4, re: regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\b`)},
+	{name: model.EntityIPv6, re: regexp.MustCompile(`(?i)(?:[0-9a-f]{0,4}:){2,7}[0-9a-f]{0,4}`), valid: isIPv6, standalone: true},
+	{name: model.EntityDate, re: regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}(?:[T ]\d{2}:\d{2}(?::\d{2})?)?\b|\b\d{1,2}/\d{1,2}/\d{2,4}\b|\b` + monthNames + `\.? \d{1,2}(?:st|nd|rd|th)?,? \d{4}\b|\b\d{1,2} ` + monthNames + `\.? \d{4}\b`)},
+	{name: model.EntityPhone, re: regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{2,4}\)\s?|\b\d{2,4}[\s.-])\d{3,4}[\s.-]?\d{3,4}\b`), valid: isPhone},
+	{name: model.EntityPrice, re: regexp.MustCompile(`[$€£¥₹]\s?\d{1,3}(?:[,.\s]\d{3})*(?:[.,]\d{1,2})?\b|\b\d{1,3}(?:[,.]\d{3})*(?:[.,]\d{1,2})?\s?(?:` + currencyCodes + `\b|[$€£¥₹])|\b` + currencyCodes + `\s?\d{1,3}(?:[,.]\d{3})*(?:[.,]\d{1,2})?\b`)},
+	// US ZIP codes need the state before them to tell them from other
+	// five-digit numbers; UK and Canadian codes stand on their own.
+	{name: model.EntityPostalCode, re: regexp.MustCompile(`\b[A-Z]{2}\s+(\d{5}(?:-\d{4})?)\b|\b([A-Z]{1,2}\d[A-Z\d]? \d[A-Z]{2})\b|\b([A-Z]\d[A-Z] ?\d[A-Z]\d)\b`)},
+}
+
+// BuiltinEntityTypes lists the entity types ExtractEntities knows.
+func BuiltinEntityTypes() []string {
+	names := make([]string, len(builtinEntities))
+	for i, p := range builtinEntities {
+		names[i] = p.name
+	}
+	return names
+}
+
+// ExtractEntities finds the built-in entity types named in types and the
+// named custom patterns in text. Each distinct value is reported once per
+// type with the [start, end) character offsets of every occurrence. A
+// pattern with capture groups reports its first non-empty group. Results are
+// ordered by first occurrence.
+func ExtractEntities(text string, types []string, patterns map[string]string) ([]model.Entity, error) {
+	var enabled []entityPattern
+	for _, t := range types {
+		if t == EntityTypeAll {
+			enabled = builtinEntities
+			break
+		}
+	}
+	if enabled == nil {
+		for _, t := range types {
+			i := slices.IndexFunc(builtinEntities, func(p entityPattern) bool { return p.name == t })
+			if i < 0 {
+				return nil, fmt.Errorf("unknown entity type %q, expected one of: %s", t, strings.Join(BuiltinEntityTypes(), ", "))
+			}
+			enabled = append(enabled, builtinEntities[i])
+		}
+		// Keep the built-in precedence whatever order types were given in.
+		slices.SortFunc(enabled, func(a, b entityPattern) int {
+			return slices.Index(BuiltinEntityTypes(), a.name) - slices.Index(BuiltinEntityTypes(), b.name)
+		})
+	}
+	names := make([]string, 0, len(patterns))
+	for name := range patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var custom []entityPattern
+	for _, name := range names {
+		if name == "" {
+			return nil, fmt.Errorf("entity pattern without a name")
+		}
+		re, err := regexp.Compile(patterns[name])
+		if err != nil {
+			return nil, fmt.Errorf("entity pattern %q: %w", name, err)
+		}
+		custom = append(custom, entityPattern{name: name, re: re})
+	}
+
+	var (
+		found  []match
+		taken  [][2]int
+		byType = map[string][]match{}
+	)
+	for _, p := range enabled {
+		for _, m := range p.matches(text) {
+			if overlaps(taken, m.span) {
+				continue
+			}
+			byType[p.name] = append(byType[p.name], m)
+		}
+		for _, m := range byType[p.name] {
+			taken = append(taken, m.span)
+		}
+		found = append(found, byType[p.name]...)
+	}
+	for _, p := range custom {
+		found = append(found, p.matches(text)...)
+	}
+	return groupMatches(text, found), nil
+}
+
+type match struct {
+	typ   string
+	value string
+	span  [2]int
+}
+
+func (p entityPattern) matches(text string) []match {
+	var out []match
+	for _, loc := range p.re.FindAllStringSubmatchIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		for g := 2; g+1 < len(loc); g += 2 {
+			if loc[g] >= 0 && loc[g+1] > loc[g] {
+				start, end = loc[g], loc[g+1]
+				break
+			}
+		}
+		value := text[start:end]
+		if p.name == model.EntityURL {
+			trimmed := strings.TrimRight(value, ".,;:!?")
+			end -= len(value) - len(trimmed)
+			value = trimmed
+		}
+		if value == "" || (p.valid != nil && !p.valid(value)) {
+			continue
+		}
+		if p.standalone && (touchesWord(text[:start], utf8.DecodeLastRuneInString) || touchesWord(text[end:], utf8.DecodeRuneInString)) {
+			continue
+		}
+		out = append(out, match{typ: p.name, value: value, span: [2]int{start, end}})
+	}
+	return out
+}
+
+func overlaps(taken [][2]int, span [2]int) bool {
+	for _, t := range taken {
+		if span[0] < t[1] && t[0] < span[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// groupMatches merges matches of the same type and value and converts their
+// byte offsets to character offsets.
+func groupMatches(text string, found []match) []model.Entity {
+	sort.SliceStable(found, func(i, j int) bool { return found[i].span[0] < found[j].span[0] })
+	chars := charOffsets(text, found)
+	var entities []model.Entity
+	index := map[[2]string]int{}
+	for _, m := range found {
+		span := [2]int{chars[m.span[0]], chars[m.span[1]]}
+		key := [2]string{m.typ, m.value}
+		if i, ok := index[key]; ok {
+			entities[i].Offsets = append(entities[i].Offsets, span)
+			continue
+		}
+		index[key] = len(entities)
+		entities = append(entities, model.Entity{Type: m.typ, Value: m.value, Offsets: [][2]int{span}})
+	}
+	return entities
+}
+
+// charOffsets maps the byte offsets of the matches to character offsets in
+// one pass over text.
+func charOffsets(text string, found []match) map[int]int {
+	var positions []int
+	for _, m := range found {
+		positions = append(positions, m.span[0], m.span[1])
+	}
+	sort.Ints(positions)
+	chars := make(map[int]int, len(positions))
+	prevByte, prevChar := 0, 0
+	for _, pos := range positions {
+		prevChar += utf8.RuneCountInString(text[prevByte:pos])
+		prevByte = pos
+		chars[pos] = prevChar
+	}
+	return chars
+}
+
+// touchesWord reports whether the rune decode reads from s, the text on one
+// side of a match, is a word character or colon.
+func touchesWord(s string, decode func(string) (rune, int)) bool {
+	r, size := decode(s)
+	return size > 0 && (r == ':' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// isIPv6 accepts full eight-group addresses and compressed ones with a
+// decimal digit, such as "::1" or "fe80::1", so "::" in code is not matched.
+func isIPv6(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	if err != nil || !addr.Is6() {
+		return false
+	}
+	return strings.Count(s, ":") == 7 && !strings.Contains(s, "::") || strings.ContainsAny(s, "0123456789")
+}
+
+// isPhone accepts 7 to 15 digits, the range of E.164 numbers.
+func isPhone(s string) bool {
+	digits := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits >= 7 && digits <= 15
+}
+
+// pageText returns the text of rawHTML's body, one line per block, skipping
+// scripts and styles.
+func pageText(rawHTML string) (string, error) {
+	doc, err := html.Parse(strings.NewReader(rawHTML))
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			if text := strings.Join(strings.Fields(n.Data), " "); text != "" {
+				if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "\n") {
+					sb.WriteByte(' ')
+				}
+				sb.WriteString(text)
+			}
+		case n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style" || n.Data == "noscript" || n.Data == "template" || n.Data == "head"):
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if n.Type == html.ElementNode && isBlock(n.Data) && sb.Len() > 0 && !strings.HasSuffix(sb.String(), "\n") {
+			sb.WriteByte('\n')
+		}
+	}
+	walk(doc)
+	return strings.TrimSpace(sb.String()), nil
+}
+
+func isBlock(tag string) bool {
+	switch tag {
+	case "p", "div", "section", "article", "header", "footer", "li", "ul", "ol", "table", "tr", "td", "th",
+		"h1", "h2", "h3", "h4", "h5", "h6", "br", "pre", "blockquote", "dd", "dt", "address":
+		return true
+	}
+	return false
+}
+
+func entitiesEnabled(cfg config.CrawlerRunConfig) bool {
+	return len(cfg.EntityTypes) > 0 || len(cfg.EntityPatterns) > 0
+}
+
+// withEntities runs the entity stage over text when cfg enables it and sets
+// out.Text to text, which the offsets index. Markdown is never matched since
+// the converter escapes characters such as "_" inside values.
+func withEntities(out Output, text string, cfg config.CrawlerRunConfig) (Output, error) {
+	if !entitiesEnabled(cfg) {
+		return out, nil
+	}
+	entities, err := ExtractEntities(text, cfg.EntityTypes, cfg.EntityPatterns)
+	if err != nil {
+		return out, err
+	}
+	out.Entities = entities
+	out.Text = text
+	return out, nil
+}
@@ -0,0 +1,83 @@
+package extract
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// feedTypes are the <link type> values that advertise a feed. Plain
+// application/json is left out: sites such as WordPress use it for API
+// endpoints, while JSON Feed has its own type.
+var feedTypes = []string{
+	"application/rss+xml",
+	"application/atom+xml",
+	"application/rdf+xml",
+	"application/feed+json",
+}
+
+// FeedLink is a feed a page advertises with <link rel="alternate">.
+type FeedLink struct {
+	URL   string `json:"url"`
+	Type  string `json:"type"`
+	Title string `json:"title,omitempty"`
+}
+
+// ExtractFeedLinks returns the RSS, Atom and JSON feeds the page links to
+// with rel="alternate", resolved against baseURL or the document's
+// <base href>, in document order.
+func ExtractFeedLinks(rawHTML, baseURL string) ([]FeedLink, error) {
+	doc, err := html.Parse(strings.NewReader(rawHTML))
+	if err != nil {
+		return nil, err
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if href, ok := findBaseHref(doc); ok {
+		if resolved, err := base.Parse(href); err == nil {
+			base = resolved
+		}
+	}
+
+	var feeds []FeedLink
+	seen := map[string]bool{}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "link" {
+			if feed, ok := feedLink(base, n); ok && !seen[feed.URL] {
+				seen[feed.URL] = true
+				feeds = append(feeds, feed)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return feeds, nil
+}
+
+func feedLink(base *url.URL, n *html.Node) (FeedLink, bool) {
+	rels := strings.Fields(strings.ToLower(attr(n, "rel")))
+	if !slices.Contains(rels, "alternate") {
+		return FeedLink{}, false
+	}
+	mediaType, _, _ := strings.Cut(strings.ToLower(attr(n, "type")), ";")
+	mediaType = strings.TrimSpace(mediaType)
+	if !slices.Contains(feedTypes, mediaType) {
+		return FeedLink{}, false
+	}
+	href := strings.TrimSpace(attr(n, "href"))
+	if href == "" {
+		return FeedLink{}, false
+	}
+	u, err := base.Parse(href)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return FeedLink{}, false
+	}
+	return FeedLink{URL: u.String(), Type: mediaType, Title: strings.TrimSpace(attr(n, "title"))}, true
+}
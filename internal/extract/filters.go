@@ -0,0 +1,407 @@
+package extract
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"golang.org/x/net/html"
+)
+
+const (
+	DefaultPruningThreshold = 0.48
+	DefaultBM25Threshold    = 1.0
+)
+
+// ContentFilter keeps the parts of an HTML page worth converting to the fit
+// Markdown and returns them as HTML.
+type ContentFilter = config.ContentFilter
+
+// ContentFilterChain applies filters in order, each to the previous output.
+type ContentFilterChain []ContentFilter
+
+func (fc ContentFilterChain) Filter(rawHTML string) (string, error) {
+	out := rawHTML
+	for _, f := range fc {
+		var err error
+		if out, err = f.Filter(out); err != nil {
+			return "", err
+		}
+	}
+	return out, nil
+}
+
+// runFilters builds the run's filter chain: its declared filters followed by
+// its custom ones. Filters see the cleaned HTML, which has no <head>, so BM25
+// filters without a query get one from the raw page.
+func runFilters(rawHTML string, cfg config.CrawlerRunConfig) (ContentFilterChain, error) {
+	specs := cfg.ContentFilters
+	if slices.ContainsFunc(specs, func(spec config.ContentFilterConfig) bool {
+		return spec.Type == config.ContentFilterBM25 && strings.TrimSpace(spec.Query) == ""
+	}) {
+		doc, err := html.Parse(strings.NewReader(rawHTML))
+		if err != nil {
+			return nil, err
+		}
+		query := pageQuery(doc)
+		specs = slices.Clone(specs)
+		for i := range specs {
+			if specs[i].Type == config.ContentFilterBM25 && strings.TrimSpace(specs[i].Query) == "" {
+				specs[i].Query = query
+			}
+		}
+	}
+	chain, err := BuildContentFilters(specs)
+	if err != nil {
+		return nil, err
+	}
+	return append(chain, cfg.CustomContentFilters...), nil
+}
+
+// BuildContentFilters turns declarative filter specs into a chain.
+func BuildContentFilters(specs []config.ContentFilterConfig) (ContentFilterChain, error) {
+	chain := make(ContentFilterChain, 0, len(specs))
+	for i, spec := range specs {
+		switch spec.Type {
+		case config.ContentFilterPruning:
+			chain = append(chain, PruningFilter{Threshold: spec.Threshold, MinWords: spec.MinWords})
+		case config.ContentFilterBM25:
+			chain = append(chain, BM25Filter{Query: spec.Query, Threshold: spec.Threshold})
+		default:
+			return nil, fmt.Errorf("content filter %d: unsupported type %q", i, spec.Type)
+		}
+	}
+	return chain, nil
+}
+
+// prunedTags never hold main content.
+var prunedTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "iframe": true,
+	"svg": true, "canvas": true, "form": true, "button": true, "input": true, "select": true,
+	"nav": true, "aside": true, "footer": true, "header": true,
+}
+
+// tagWeights favor elements that usually carry content.
+var tagWeights = map[string]float64{
+	"article": 1.5, "main": 1.4, "section": 1.2, "p": 1.2, "blockquote": 1.1, "pre": 1.1,
+	"h1": 1.4, "h2": 1.3, "h3": 1.2, "h4": 1.1, "h5": 1.0, "h6": 1.0,
+	"table": 1.0, "figure": 1.0, "ul": 0.8, "ol": 0.9, "li": 0.8, "dl": 0.9,
+	"div": 0.6, "span": 0.5, "td": 0.7, "tr": 0.7,
+}
+
+var (
+	negativeClassID = regexp.MustCompile(`(?i)nav|menu|sidebar|footer|header|banner|breadcrumb|comment|share|social|related|promo|advert|\bads?\b|sponsor|cookie|popup|modal|newsletter|subscribe|widget`)
+	positiveClassID = regexp.MustCompile(`(?i)article|content|main|post|entry|story|body|text|blog`)
+)
+
+// PruningFilter drops page blocks that score below Threshold on text
+// density, link density, tag weight, class and id hints and text length,
+// then drops text blocks shorter than MinWords words. Navigation, forms and
+// scripts are always removed.
+type PruningFilter struct {
+	Threshold float64
+	MinWords  int
+}
+
+type blockStats struct {
+	textLen, linkTextLen, markupLen int
+}
+
+func (f PruningFilter) Filter(rawHTML string) (string, error) {
+	doc, err := html.Parse(strings.NewReader(rawHTML))
+	if err != nil {
+		return "", err
+	}
+	body := findElement(doc, "body")
+	if body == nil {
+		body = doc
+	}
+	threshold := f.Threshold
+	if threshold <= 0 {
+		threshold = DefaultPruningThreshold
+	}
+	removeTags(body, prunedTags)
+
+	stats := map[*html.Node]blockStats{}
+	measure(body, false, stats)
+	var prune func(n *html.Node)
+	prune = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; {
+			next := c.NextSibling
+			if c.Type == html.ElementNode && (isBlock(c.Data) || c.Data == "main" || c.Data == "figure" || c.Data == "dl") {
+				st := stats[c]
+				switch {
+				case st.textLen == 0 && !containsElement(c, "img"):
+					n.RemoveChild(c)
+				case pruneScore(c, st) < threshold:
+					n.RemoveChild(c)
+				case f.MinWords > 0 && isTextBlock(c.Data) && len(strings.Fields(textContent(c))) < f.MinWords:
+					n.RemoveChild(c)
+				case c.Data != "table":
+					// Tables are kept or dropped whole so columns stay aligned.
+					prune(c)
+				}
+			}
+			c = next
+		}
+	}
+	prune(body)
+	// The head stays so later filters can read the title and description.
+	return renderChildren(doc)
+}
+
+// pruneScore weighs a block's metrics into a score around 0 to 1.
+func pruneScore(n *html.Node, st blockStats) float64 {
+	textDensity := float64(st.textLen) / float64(max(st.markupLen, 1))
+	linkDensity := float64(st.linkTextLen) / float64(max(st.textLen, 1))
+	tagWeight, ok := tagWeights[n.Data]
+	if !ok {
+		tagWeight = 0.5
+	}
+	classID := 0.0
+	hints := attr(n, "class") + " " + attr(n, "id")
+	if strings.TrimSpace(hints) != "" {
+		if negativeClassID.MatchString(hints) {
+			classID -= 0.5
+		}
+		if positiveClassID.MatchString(hints) {
+			classID += 0.5
+		}
+	}
+	lengthScore := math.Log(float64(st.textLen)+1) / math.Log(1000)
+	return 0.4*textDensity + 0.2*(1-linkDensity) + 0.2*tagWeight + 0.1*classID + 0.1*lengthScore
+}
+
+// measure fills stats for n's subtree in one pass and returns n's totals.
+// markupLen approximates the rendered size of the subtree.
+func measure(n *html.Node, inLink bool, stats map[*html.Node]blockStats) blockStats {
+	var st blockStats
+	switch n.Type {
+	case html.TextNode:
+		l := len(collapse(n.Data))
+		st.textLen, st.markupLen = l, l
+		if inLink {
+			st.linkTextLen = l
+		}
+		return st
+	case html.ElementNode:
+		st.markupLen = 2*len(n.Data) + 5
+		for _, a := range n.Attr {
+			st.markupLen += len(a.Key) + len(a.Val) + 4
+		}
+		inLink = inLink || n.Data == "a"
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		cs := measure(c, inLink, stats)
+		st.textLen += cs.textLen
+		st.linkTextLen += cs.linkTextLen
+		st.markupLen += cs.markupLen
+	}
+	stats[n] = st
+	return st
+}
+
+func isTextBlock(tag string) bool {
+	switch tag {
+	case "p", "li", "blockquote", "dd", "td":
+		return true
+	}
+	return false
+}
+
+// BM25Filter keeps the text blocks relevant to Query, scored with Okapi
+// BM25 over the page's blocks, in page order. Headings count double. An
+// empty Query uses the page title, description and first heading.
+type BM25Filter struct {
+	Query     string
+	Threshold float64
+}
+
+// bm25Blocks are the elements scored as one unit of text.
+var bm25Blocks = map[string]bool{
+	"p": true, "li": true, "blockquote": true, "pre": true, "dd": true, "dt": true,
+	"td": true, "th": true, "figcaption": true, "caption": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+func (f BM25Filter) Filter(rawHTML string) (string, error) {
+	doc, err := html.Parse(strings.NewReader(rawHTML))
+	if err != nil {
+		return "", err
+	}
+	threshold := f.Threshold
+	if threshold <= 0 {
+		threshold = DefaultBM25Threshold
+	}
+	query := f.Query
+	if strings.TrimSpace(query) == "" {
+		query = pageQuery(doc)
+	}
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return "", nil
+	}
+	removeTags(doc, prunedTags)
+
+	var blocks []*html.Node
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.ElementNode && bm25Blocks[n.Data] {
+			blocks = append(blocks, n)
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	collect(doc)
+
+	docs := make([][]string, len(blocks))
+	df := map[string]int{}
+	totalLen := 0
+	for i, b := range blocks {
+		docs[i] = tokenize(textContent(b))
+		totalLen += len(docs[i])
+		seen := map[string]bool{}
+		for _, t := range docs[i] {
+			if !seen[t] {
+				seen[t] = true
+				df[t]++
+			}
+		}
+	}
+	if totalLen == 0 {
+		return "", nil
+	}
+	avgLen := float64(totalLen) / float64(len(blocks))
+
+	const k1, b = 1.2, 0.75
+	var out bytes.Buffer
+	for i, block := range blocks {
+		tf := map[string]int{}
+		for _, t := range docs[i] {
+			tf[t]++
+		}
+		score := 0.0
+		for _, term := range terms {
+			n := df[term]
+			if n == 0 {
+				continue
+			}
+			idf := math.Log((float64(len(blocks))-float64(n)+0.5)/(float64(n)+0.5) + 1)
+			freq := float64(tf[term])
+			score += idf * freq * (k1 + 1) / (freq + k1*(1-b+b*float64(len(docs[i]))/avgLen))
+		}
+		if block.Data[0] == 'h' {
+			score *= 2
+		}
+		if score >= threshold {
+			if err := html.Render(&out, block); err != nil {
+				return "", err
+			}
+			out.WriteByte('\n')
+		}
+	}
+	return out.String(), nil
+}
+
+// pageQuery builds a query from the page's title, description and first
+// heading.
+func pageQuery(doc *html.Node) string {
+	var parts []string
+	if t := findElement(doc, "title"); t != nil {
+		parts = append(parts, textContent(t))
+	}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "meta" && strings.EqualFold(attr(n, "name"), "description") {
+			parts = append(parts, attr(n, "content"))
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	if h := findElement(doc, "h1"); h != nil {
+		parts = append(parts, textContent(h))
+	}
+	return strings.Join(parts, " ")
+}
+
+var (
+	wordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+	stopWords   = map[string]bool{
+		"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+		"for": true, "from": true, "has": true, "in": true, "is": true, "it": true, "of": true, "on": true,
+		"or": true, "that": true, "the": true, "this": true, "to": true, "was": true, "with": true,
+	}
+)
+
+// tokenize lower-cases text, drops stop words and strips common English
+// suffixes so "pricing" matches "prices".
+func tokenize(text string) []string {
+	var tokens []string
+	for _, w := range wordPattern.FindAllString(strings.ToLower(text), -1) {
+		if stopWords[w] {
+			continue
+		}
+		tokens = append(tokens, stem(w))
+	}
+	return tokens
+}
+
+func stem(w string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s", "e"} {
+		if len(w)-len(suffix) >= 3 && strings.HasSuffix(w, suffix) {
+			return strings.TrimSuffix(w, suffix)
+		}
+	}
+	return w
+}
+
+func removeTags(n *html.Node, tags map[string]bool) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.ElementNode && tags[c.Data] || c.Type == html.CommentNode {
+			n.RemoveChild(c)
+		} else {
+			removeTags(c, tags)
+		}
+		c = next
+	}
+}
+
+func findElement(n *html.Node, tag string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == tag {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func containsElement(n *html.Node, tag string) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if findElement(c, tag) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func renderChildren(n *html.Node) (string, error) {
+	var buf bytes.Buffer
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&buf, c); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
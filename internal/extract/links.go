@@ -0,0 +1,114 @@
+package extract
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/techbysteve/prowl4ai/internal/model"
+	"golang.org/x/net/html"
+)
+
+// ExtractLinks collects the page's <a href> links, resolved against baseURL
+// or the document's <base href>, with fragments removed. Links are split into
+// internal ones on the page's host (ignoring a leading "www.") and external
+// ones. Non-HTTP schemes such as mailto: and javascript: are skipped.
+func ExtractLinks(rawHTML, baseURL string) (model.Links, error) {
+	doc, err := html.Parse(strings.NewReader(rawHTML))
+	if err != nil {
+		return model.Links{}, err
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return model.Links{}, err
+	}
+	if href, ok := findBaseHref(doc); ok {
+		if resolved, err := base.Parse(href); err == nil {
+			base = resolved
+		}
+	}
+	pageHost := bareHost(base.Hostname())
+
+	var links model.Links
+	seen := map[string]bool{}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "a" {
+			if link, ok := resolveLink(base, n); ok && !seen[link.Href] {
+				seen[link.Href] = true
+				u, _ := url.Parse(link.Href)
+				if bareHost(u.Hostname()) == pageHost {
+					links.Internal = append(links.Internal, link)
+				} else {
+					links.External = append(links.External, link)
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return links, nil
+}
+
+func resolveLink(base *url.URL, n *html.Node) (model.Link, bool) {
+	href := strings.TrimSpace(attr(n, "href"))
+	if href == "" || strings.HasPrefix(href, "#") {
+		return model.Link{}, false
+	}
+	u, err := base.Parse(href)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return model.Link{}, false
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+	return model.Link{
+		Href:  u.String(),
+		Text:  strings.Join(strings.Fields(textContent(n)), " "),
+		Title: attr(n, "title"),
+		Rel:   attr(n, "rel"),
+	}, true
+}
+
+func findBaseHref(n *html.Node) (string, bool) {
+	if n.Type == html.ElementNode && n.Data == "base" {
+		if href := attr(n, "href"); href != "" {
+			return href, true
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if href, ok := findBaseHref(c); ok {
+			return href, true
+		}
+	}
+	return "", false
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func textContent(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			sb.WriteString(" ")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
+func bareHost(host string) string {
+	return strings.TrimPrefix(strings.ToLower(host), "www.")
+}
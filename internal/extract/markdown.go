@@ -1,13 +1,146 @@
 package extract
 
 import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
 	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"golang.org/x/net/html"
+)
+
+// MarkdownOptions controls ToMarkdown.
+type MarkdownOptions struct {
+	// Citations replaces inline links with numbered references, "text [1]",
+	// and appends a References section listing each URL once with its title.
+	Citations bool
+}
+
+// citeOpen and citeClose wrap citation numbers through the conversion so
+// the converter does not escape their brackets.
+const (
+	citeOpen  = "\uE000"
+	citeClose = "\uE001"
 )
 
-func ToMarkdown(cleanHTML string, pageURL string) (string, error) {
-	markdown, err := htmltomarkdown.ConvertString(cleanHTML)
+var citeMarker = regexp.MustCompile(citeOpen + `(\d+)` + citeClose)
+
+// ToMarkdown converts cleanHTML to Markdown, resolving relative link and
+// image URLs against the document's <base href>, if any, or else pageURL.
+func ToMarkdown(cleanHTML string, pageURL string, opts MarkdownOptions) (string, error) {
+	doc, err := html.Parse(strings.NewReader(cleanHTML))
 	if err != nil {
 		return "", err
 	}
-	return markdown, nil
+	base := documentBase(doc, pageURL)
+	if !opts.Citations {
+		markdown, err := htmltomarkdown.ConvertNode(doc, converter.WithDomain(base.String()))
+		return string(markdown), err
+	}
+
+	refs := citeLinks(doc, base)
+	markdown, err := htmltomarkdown.ConvertNode(doc, converter.WithDomain(base.String()))
+	if err != nil {
+		return "", err
+	}
+	out := citeMarker.ReplaceAllString(string(markdown), "[$1]")
+	if len(refs) == 0 {
+		return out, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strings.TrimRight(out, "\n"))
+	sb.WriteString("\n\n## References\n\n")
+	for i, ref := range refs {
+		fmt.Fprintf(&sb, "- [%d] <%s>", i+1, ref.url)
+		if ref.title != "" {
+			fmt.Fprintf(&sb, " %s", ref.title)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+// documentBase returns pageURL resolved against doc's <base href>.
+func documentBase(doc *html.Node, pageURL string) *url.URL {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		base = &url.URL{}
+	}
+	if href, ok := findBaseHref(doc); ok {
+		if resolved, err := base.Parse(href); err == nil {
+			base = resolved
+		}
+	}
+	return base
+}
+
+type citation struct {
+	url   string
+	title string
+}
+
+// citeLinks replaces each <a> in doc with its content, followed by a
+// citation marker for links to other pages, numbering URLs in order of first
+// appearance, with URLs resolved against base. In-page and script links are
+// dropped without a citation.
+func citeLinks(doc *html.Node, base *url.URL) []citation {
+	var (
+		refs    []citation
+		numbers = map[string]int{}
+		anchors []*html.Node
+	)
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "a" {
+			anchors = append(anchors, n)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	for _, a := range anchors {
+		// Keep the link's content in place.
+		parent := a.Parent
+		for c := a.FirstChild; c != nil; {
+			next := c.NextSibling
+			a.RemoveChild(c)
+			parent.InsertBefore(c, a)
+			c = next
+		}
+		if target, ok := citeTarget(base, attr(a, "href")); ok {
+			num, seen := numbers[target]
+			if !seen {
+				title := collapse(attr(a, "title"))
+				if title == "" {
+					title = collapse(textContent(a))
+				}
+				refs = append(refs, citation{url: target, title: title})
+				num = len(refs)
+				numbers[target] = num
+			}
+			marker := fmt.Sprintf(" %s%d%s", citeOpen, num, citeClose)
+			parent.InsertBefore(&html.Node{Type: html.TextNode, Data: marker}, a)
+		}
+		parent.RemoveChild(a)
+	}
+	return refs
+}
+
+// citeTarget resolves href and reports whether it leads off the page to a
+// web or mail address.
+func citeTarget(base *url.URL, href string) (string, bool) {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") {
+		return "", false
+	}
+	u, err := base.Parse(href)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "mailto") {
+		return "", false
+	}
+	return u.String(), true
 }
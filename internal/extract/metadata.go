@@ -0,0 +1,543 @@
+package extract
+
+import (
+	"encoding/json"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// HreflangLink is a translation of the page declared with
+// <link rel="alternate" hreflang="...">.
+type HreflangLink struct {
+	Lang string `json:"lang"`
+	URL  string `json:"url"`
+}
+
+// StructuredItem is a microdata or RDFa item normalized to the microdata JSON
+// form: full type IRIs and every property as a list of strings or nested
+// items.
+type StructuredItem struct {
+	Type       []string         `json:"type,omitempty"`
+	ID         string           `json:"id,omitempty"`
+	Properties map[string][]any `json:"properties"`
+}
+
+// maxMicrodataVisits caps the elements read for a page's microdata, since
+// itemref lets a page reach the same subtree from many items.
+const maxMicrodataVisits = 100000
+
+// openGraphPrefixes are the <meta property> namespaces collected as
+// OpenGraph; og: is dropped from keys, the others keep their prefix.
+var openGraphPrefixes = []string{"og:", "article:", "book:", "profile:", "music:", "video:", "fb:"}
+
+// publishedNames and modifiedNames are the <meta> names and properties that
+// carry page dates, in order of preference.
+var (
+	publishedNames = []string{"article:published_time", "og:published_time", "datepublished", "date", "dc.date", "dc.date.issued", "dcterms.created", "dcterms.issued", "pubdate", "publish_date"}
+	modifiedNames  = []string{"article:modified_time", "og:updated_time", "datemodified", "last-modified", "dcterms.modified", "dc.date.modified"}
+)
+
+// ExtractMetadata reads the metadata a page declares in its markup. Only
+// keys with values are set:
+//
+//   - canonical, description, keywords, favicon
+//   - open_graph and twitter: <meta> tags by property name; repeated
+//     properties become lists
+//   - hreflang: translations as []HreflangLink
+//   - published and modified: RFC 3339 when the date parses
+//   - json_ld: the parsed application/ld+json blocks, arrays flattened
+//   - microdata and rdfa: top-level items as []StructuredItem
+//   - feeds: advertised feeds as []FeedLink
+//
+// URLs are resolved against baseURL or the document's <base href>.
+func ExtractMetadata(rawHTML, baseURL string) (map[string]any, error) {
+	doc, err := html.Parse(strings.NewReader(rawHTML))
+	if err != nil {
+		return nil, err
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if href, ok := findBaseHref(doc); ok {
+		if resolved, err := base.Parse(href); err == nil {
+			base = resolved
+		}
+	}
+
+	m := &metaCollector{
+		base:      base,
+		openGraph: map[string]any{},
+		twitter:   map[string]string{},
+		dates:     map[string]string{},
+		seenFeeds: map[string]bool{},
+		budget:    maxMicrodataVisits,
+	}
+	m.walk(doc, false)
+
+	out := map[string]any{}
+	setString(out, "canonical", m.canonical)
+	setString(out, "description", m.description)
+	if len(m.keywords) > 0 {
+		out["keywords"] = m.keywords
+	}
+	if m.favicon == "" {
+		m.favicon = m.touchIcon
+	}
+	setString(out, "favicon", m.favicon)
+	if len(m.openGraph) > 0 {
+		out["open_graph"] = m.openGraph
+	}
+	if len(m.twitter) > 0 {
+		out["twitter"] = m.twitter
+	}
+	if len(m.hreflang) > 0 {
+		out["hreflang"] = m.hreflang
+	}
+	if len(m.jsonLD) > 0 {
+		out["json_ld"] = m.jsonLD
+	}
+	if len(m.microdata) > 0 {
+		out["microdata"] = m.microdata
+	}
+	if len(m.rdfa) > 0 {
+		out["rdfa"] = m.rdfa
+	}
+	if len(m.feeds) > 0 {
+		out["feeds"] = m.feeds
+	}
+	setString(out, "published", m.date(publishedNames, "datePublished"))
+	setString(out, "modified", m.date(modifiedNames, "dateModified"))
+	return out, nil
+}
+
+func setString(out map[string]any, key, value string) {
+	if value != "" {
+		out[key] = value
+	}
+}
+
+type metaCollector struct {
+	base        *url.URL
+	canonical   string
+	description string
+	keywords    []string
+	favicon     string
+	touchIcon   string
+	openGraph   map[string]any
+	twitter     map[string]string
+	hreflang    []HreflangLink
+	dates       map[string]string
+	jsonLD      []any
+	microdata   []StructuredItem
+	rdfa        []StructuredItem
+	feeds       []FeedLink
+	seenFeeds   map[string]bool
+	budget      int
+}
+
+// walk visits n's subtree. inItem is set below a microdata or RDFa item, whose
+// nested items are reached through their parent rather than listed again.
+func (m *metaCollector) walk(n *html.Node, inItem bool) {
+	if n.Type == html.ElementNode {
+		switch n.Data {
+		case "meta":
+			m.meta(n)
+		case "link":
+			m.link(n)
+		case "script":
+			if strings.EqualFold(strings.TrimSpace(attr(n, "type")), "application/ld+json") {
+				m.ldJSON(textOf(n))
+			}
+		case "time":
+			if prop := attr(n, "itemprop"); prop != "" {
+				m.addDate(strings.ToLower(prop), attr(n, "datetime"))
+			}
+		}
+		if !inItem {
+			if hasAttr(n, "itemscope") && attr(n, "itemprop") == "" {
+				m.microdata = append(m.microdata, m.microdataItem(n, map[*html.Node]bool{}))
+				inItem = true
+			} else if hasAttr(n, "typeof") && attr(n, "property") == "" {
+				m.rdfa = append(m.rdfa, m.rdfaItem(n, vocabOf(n)))
+				inItem = true
+			}
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		m.walk(c, inItem)
+	}
+}
+
+func (m *metaCollector) meta(n *html.Node) {
+	content := strings.TrimSpace(attr(n, "content"))
+	if content == "" {
+		return
+	}
+	name := strings.ToLower(strings.TrimSpace(attr(n, "name")))
+	property := strings.ToLower(strings.TrimSpace(attr(n, "property")))
+	switch {
+	case name == "description" && m.description == "":
+		m.description = content
+	case name == "keywords" && m.keywords == nil:
+		for _, k := range strings.Split(content, ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				m.keywords = append(m.keywords, k)
+			}
+		}
+	}
+
+	// Twitter cards use name= but many sites write property=.
+	for _, key := range []string{name, property} {
+		if tw, ok := strings.CutPrefix(key, "twitter:"); ok {
+			if _, exists := m.twitter[tw]; !exists {
+				m.twitter[tw] = content
+			}
+		}
+	}
+	if property != "" && hasAnyPrefix(property, openGraphPrefixes) {
+		key := strings.TrimPrefix(property, "og:")
+		switch prev := m.openGraph[key].(type) {
+		case nil:
+			m.openGraph[key] = content
+		case string:
+			m.openGraph[key] = []string{prev, content}
+		case []string:
+			m.openGraph[key] = append(prev, content)
+		}
+	}
+
+	for _, key := range []string{name, property, strings.ToLower(attr(n, "itemprop")), strings.ToLower(attr(n, "http-equiv"))} {
+		m.addDate(key, content)
+	}
+}
+
+func (m *metaCollector) addDate(key, value string) {
+	value = strings.TrimSpace(value)
+	if key == "" || value == "" {
+		return
+	}
+	if _, exists := m.dates[key]; !exists {
+		m.dates[key] = value
+	}
+}
+
+func (m *metaCollector) link(n *html.Node) {
+	rels := strings.Fields(strings.ToLower(attr(n, "rel")))
+	href := m.resolve(attr(n, "href"))
+	if href == "" {
+		return
+	}
+	switch {
+	case slices.Contains(rels, "canonical"):
+		if m.canonical == "" {
+			m.canonical = href
+		}
+	case slices.Contains(rels, "icon"):
+		if m.favicon == "" {
+			m.favicon = href
+		}
+	case slices.Contains(rels, "apple-touch-icon"):
+		if m.touchIcon == "" {
+			m.touchIcon = href
+		}
+	case slices.Contains(rels, "alternate"):
+		if lang := strings.TrimSpace(attr(n, "hreflang")); lang != "" {
+			m.hreflang = append(m.hreflang, HreflangLink{Lang: lang, URL: href})
+		} else if feed, ok := feedLink(m.base, n); ok && !m.seenFeeds[feed.URL] {
+			m.seenFeeds[feed.URL] = true
+			m.feeds = append(m.feeds, feed)
+		}
+	}
+}
+
+// ldJSON keeps the blocks that parse; malformed JSON-LD is common and skipped.
+func (m *metaCollector) ldJSON(text string) {
+	var v any
+	if err := json.Unmarshal([]byte(strings.TrimSpace(text)), &v); err != nil {
+		return
+	}
+	if list, ok := v.([]any); ok {
+		m.jsonLD = append(m.jsonLD, list...)
+		return
+	}
+	m.jsonLD = append(m.jsonLD, v)
+}
+
+// date returns the first date found under names, then in JSON-LD under
+// ldKey.
+func (m *metaCollector) date(names []string, ldKey string) string {
+	for _, name := range names {
+		if v, ok := m.dates[name]; ok {
+			return normalizeDate(v)
+		}
+	}
+	for _, v := range m.jsonLD {
+		if s := findString(v, ldKey); s != "" {
+			return normalizeDate(s)
+		}
+	}
+	return ""
+}
+
+// findString returns the first string value of key in a parsed JSON tree.
+func findString(v any, key string) string {
+	switch v := v.(type) {
+	case map[string]any:
+		if s, ok := v[key].(string); ok && strings.TrimSpace(s) != "" {
+			return strings.TrimSpace(s)
+		}
+		for _, child := range v {
+			if s := findString(child, key); s != "" {
+				return s
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if s := findString(child, key); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02 15:04:05",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.DateOnly,
+}
+
+// normalizeDate rewrites parseable dates as RFC 3339 and keeps others as
+// written.
+func normalizeDate(value string) string {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == time.DateOnly {
+				return t.Format(time.DateOnly)
+			}
+			return t.Format(time.RFC3339)
+		}
+	}
+	return value
+}
+
+// microdataItem reads the item rooted at n, including properties pulled in
+// with itemref. path holds the items being read above n; an itemref to an
+// element containing n, or a nested item already on the path, is skipped so
+// a hostile page cannot make the walk cycle.
+func (m *metaCollector) microdataItem(n *html.Node, path map[*html.Node]bool) StructuredItem {
+	path[n] = true
+	defer delete(path, n)
+
+	item := StructuredItem{Type: strings.Fields(attr(n, "itemtype")), ID: strings.TrimSpace(attr(n, "itemid")), Properties: map[string][]any{}}
+	roots := []*html.Node{n}
+	for _, id := range strings.Fields(attr(n, "itemref")) {
+		if ref := findByID(rootOf(n), id); ref != nil && !isAncestorOrSelf(ref, n) && !slices.Contains(roots, ref) {
+			roots = append(roots, ref)
+		}
+	}
+	var visit func(c *html.Node)
+	visit = func(c *html.Node) {
+		if c.Type != html.ElementNode {
+			return
+		}
+		if path[c] || m.budget <= 0 {
+			return
+		}
+		m.budget--
+		if props := strings.Fields(attr(c, "itemprop")); len(props) > 0 {
+			var value any
+			if hasAttr(c, "itemscope") {
+				value = m.microdataItem(c, path)
+			} else {
+				value = m.propertyValue(c, "")
+			}
+			for _, p := range props {
+				item.Properties[p] = append(item.Properties[p], value)
+			}
+		}
+		if hasAttr(c, "itemscope") {
+			return
+		}
+		for cc := c.FirstChild; cc != nil; cc = cc.NextSibling {
+			visit(cc)
+		}
+	}
+	for i, root := range roots {
+		if i == 0 {
+			for c := root.FirstChild; c != nil; c = c.NextSibling {
+				visit(c)
+			}
+			continue
+		}
+		visit(root)
+	}
+	return item
+}
+
+// rdfaItem reads the RDFa Lite item rooted at n, expanding terms against
+// vocab.
+func (m *metaCollector) rdfaItem(n *html.Node, vocab string) StructuredItem {
+	item := StructuredItem{ID: m.resolve(attr(n, "resource")), Properties: map[string][]any{}}
+	for _, t := range strings.Fields(attr(n, "typeof")) {
+		item.Type = append(item.Type, expandTerm(vocab, t))
+	}
+	var visit func(c *html.Node, vocab string)
+	visit = func(c *html.Node, vocab string) {
+		if c.Type != html.ElementNode {
+			return
+		}
+		if v := attr(c, "vocab"); v != "" {
+			vocab = v
+		}
+		if props := strings.Fields(attr(c, "property")); len(props) > 0 {
+			var value any
+			if hasAttr(c, "typeof") {
+				value = m.rdfaItem(c, vocab)
+			} else {
+				value = m.propertyValue(c, attr(c, "resource"))
+			}
+			for _, p := range props {
+				item.Properties[p] = append(item.Properties[p], value)
+			}
+		}
+		if hasAttr(c, "typeof") {
+			return
+		}
+		for cc := c.FirstChild; cc != nil; cc = cc.NextSibling {
+			visit(cc, vocab)
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		visit(c, vocab)
+	}
+	return item
+}
+
+// propertyValue is a property's value following the microdata rules, which
+// RDFa Lite shares: content, then the element's URL or machine-readable
+// attribute, then its text.
+func (m *metaCollector) propertyValue(n *html.Node, resource string) string {
+	if hasAttr(n, "content") {
+		return strings.TrimSpace(attr(n, "content"))
+	}
+	if resource != "" {
+		return m.resolve(resource)
+	}
+	switch n.Data {
+	case "audio", "embed", "iframe", "img", "source", "track", "video":
+		return m.resolve(attr(n, "src"))
+	case "a", "area", "link":
+		return m.resolve(attr(n, "href"))
+	case "object":
+		return m.resolve(attr(n, "data"))
+	case "data", "meter":
+		return strings.TrimSpace(attr(n, "value"))
+	case "time":
+		if hasAttr(n, "datetime") {
+			return strings.TrimSpace(attr(n, "datetime"))
+		}
+	}
+	return strings.Join(strings.Fields(textContent(n)), " ")
+}
+
+func (m *metaCollector) resolve(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	u, err := m.base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
+
+// vocabOf returns the RDFa vocab in scope at n.
+func vocabOf(n *html.Node) string {
+	for ; n != nil; n = n.Parent {
+		if n.Type == html.ElementNode {
+			if v := attr(n, "vocab"); v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// expandTerm turns a bare RDFa term into an IRI under vocab; IRIs and
+// prefixed names are kept.
+func expandTerm(vocab, term string) string {
+	if vocab == "" || strings.Contains(term, ":") {
+		return term
+	}
+	return vocab + term
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// textOf returns the raw text of n's children, as script contents need.
+func textOf(n *html.Node) string {
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+		}
+	}
+	return sb.String()
+}
+
+func rootOf(n *html.Node) *html.Node {
+	for n.Parent != nil {
+		n = n.Parent
+	}
+	return n
+}
+
+// isAncestorOrSelf reports whether n is a or one of a's descendants.
+func isAncestorOrSelf(a, n *html.Node) bool {
+	for ; n != nil; n = n.Parent {
+		if n == a {
+			return true
+		}
+	}
+	return false
+}
+
+func findByID(n *html.Node, id string) *html.Node {
+	if n.Type == html.ElementNode && attr(n, "id") == id {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findByID(c, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package extract
+
+import "testing"
+
+func TestExtractMetadataMicrodataItemrefCycles(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+	}{
+		{
+			name: "itemref to ancestor",
+			html: `<div itemscope><div id="a"><div itemprop="p" itemscope itemref="a"></div></div></div>`,
+		},
+		{
+			name: "itemref to self",
+			html: `<div itemscope id="a" itemref="a"><span itemprop="name">x</span></div>`,
+		},
+		{
+			name: "items referencing each other",
+			html: `<div itemscope itemref="b"><span itemprop="name">top</span></div>
+				<div id="b"><div itemprop="child" itemscope itemref="c"></div></div>
+				<div id="c"><div itemprop="grandchild" itemscope itemref="b"></div></div>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, err := ExtractMetadata(tt.html, "https://example.com/")
+			if err != nil {
+				t.Fatalf("ExtractMetadata: %v", err)
+			}
+			items, ok := meta["microdata"].([]StructuredItem)
+			if !ok || len(items) != 1 {
+				t.Fatalf("microdata = %#v, want one item", meta["microdata"])
+			}
+		})
+	}
+}
+
+func TestExtractMetadataMicrodataItemref(t *testing.T) {
+	page := `<div itemscope itemtype="https://schema.org/Person" itemref="addr">
+			<span itemprop="name">Ada</span>
+		</div>
+		<p id="addr" itemprop="address" itemscope><span itemprop="locality">London</span></p>`
+	meta, err := ExtractMetadata(page, "https://example.com/")
+	if err != nil {
+		t.Fatalf("ExtractMetadata: %v", err)
+	}
+	items := meta["microdata"].([]StructuredItem)
+	if len(items) != 1 {
+		t.Fatalf("got %d items, want 1", len(items))
+	}
+	person := items[0]
+	if got := person.Properties["name"]; len(got) != 1 || got[0] != "Ada" {
+		t.Errorf("name = %v, want [Ada]", got)
+	}
+	addr, ok := person.Properties["address"][0].(StructuredItem)
+	if !ok {
+		t.Fatalf("address = %#v, want a nested item", person.Properties["address"])
+	}
+	if got := addr.Properties["locality"]; len(got) != 1 || got[0] != "London" {
+		t.Errorf("locality = %v, want [London]", got)
+	}
+}
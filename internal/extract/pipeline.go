@@ -1,11 +1,28 @@
 package extract
 
-import "github.com/techbysteve/prowl4ai/internal/config"
+import (
+	"strings"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/model"
+	"golang.org/x/net/html"
+)
 
 type Output struct {
 	CleanedHTML string
 	Markdown    string
+	// FitMarkdown is the Markdown of what the run's content filters kept.
+	FitMarkdown string
 	Metadata    map[string]any
+	Links       model.Links
+	// ExtractedContent is set when the run has an extraction schema.
+	ExtractedContent []map[string]any
+	// Entities is set when the run enables entity extraction, with offsets
+	// into Text, the page text they were matched in.
+	Entities []model.Entity
+	Text     string
+	// Tables is set when the run enables table extraction.
+	Tables []model.Table
 }
 
 func Process(rawHTML, baseURL string, cfg config.CrawlerRunConfig) (Output, error) {
@@ -14,8 +31,41 @@ func Process(rawHTML, baseURL string, cfg config.CrawlerRunConfig) (Output, erro
 		Metadata:    map[string]any{},
 	}
 
+	if cfg.EnableLinks {
+		links, err := ExtractLinks(rawHTML, baseURL)
+		if err != nil {
+			return out, err
+		}
+		out.Links = links
+	}
+
+	if cfg.ExtractionSchema != nil {
+		items, err := ExtractSchema(rawHTML, baseURL, *cfg.ExtractionSchema)
+		if err != nil {
+			return out, err
+		}
+		out.ExtractedContent = items
+	}
+
+	// Tables are read from the raw page; readability often drops them.
+	if cfg.EnableTables {
+		tables, err := ExtractTables(rawHTML)
+		if err != nil {
+			return out, err
+		}
+		out.Tables = tables
+	}
+
+	// Readability resolves relative URLs itself but ignores <base href>, which
+	// the cleaned page no longer has, so it and the Markdown converter are
+	// given the document's base.
+	docBase := baseURL
+	if doc, err := html.Parse(strings.NewReader(rawHTML)); err == nil {
+		docBase = documentBase(doc, baseURL).String()
+	}
+
 	if cfg.EnableCleanHTML {
-		cleaned, metadata, err := CleanHTML(rawHTML, baseURL, cfg.OnlyText)
+		cleaned, metadata, err := CleanHTML(rawHTML, docBase, cfg.OnlyText)
 		if err != nil {
 			return out, err
 		}
@@ -27,18 +77,58 @@ func Process(rawHTML, baseURL string, cfg config.CrawlerRunConfig) (Output, erro
 		}
 	}
 
+	// Readability drops the <head>, so declared metadata is read from the raw
+	// page. Its keys never replace the ones readability set.
+	if declared, err := ExtractMetadata(rawHTML, baseURL); err == nil {
+		for key, value := range declared {
+			if _, exists := out.Metadata[key]; !exists {
+				out.Metadata[key] = value
+			}
+		}
+	}
+
+	mdOpts := MarkdownOptions{Citations: cfg.EnableCitations}
+
+	// Filters run on the cleaned page, between CleanHTML and ToMarkdown.
+	if len(cfg.ContentFilters) > 0 || len(cfg.CustomContentFilters) > 0 {
+		filters, err := runFilters(rawHTML, cfg)
+		if err != nil {
+			return out, err
+		}
+		input := out.CleanedHTML
+		if input == "" {
+			input = rawHTML
+		}
+		fitHTML, err := filters.Filter(input)
+		if err != nil {
+			return out, err
+		}
+		if out.FitMarkdown, err = ToMarkdown(fitHTML, docBase, mdOpts); err != nil {
+			return out, err
+		}
+	}
+
 	if cfg.EnableMarkdown {
 		input := out.CleanedHTML
 		if input == "" {
 			input = rawHTML
 		}
 
-		markdown, err := ToMarkdown(input, baseURL)
+		markdown, err := ToMarkdown(input, docBase, mdOpts)
 		if err != nil {
 			return out, err
 		}
 		out.Markdown = markdown
 	}
 
-	return out, nil
+	// Entities are matched in the whole page's text, so contact details in
+	// headers and footers that readability drops are still found.
+	if !entitiesEnabled(cfg) {
+		return out, nil
+	}
+	text, err := pageText(rawHTML)
+	if err != nil {
+		return out, err
+	}
+	return withEntities(out, text, cfg)
 }
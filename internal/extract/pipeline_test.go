@@ -0,0 +1,72 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+)
+
+func TestProcessEntityOffsetsIndexText(t *testing.T) {
+	cfg := config.DefaultCrawlerRunConfig()
+	cfg.EntityTypes = []string{"email"}
+	page := `<html><body><header>Write to first_last@example.com</header>
+		<p>Café orders: orders@example.com or first_last@example.com.</p></body></html>`
+	out, err := Process(page, "https://example.com/", cfg)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if len(out.Entities) != 2 {
+		t.Fatalf("got %d entities, want 2: %+v", len(out.Entities), out.Entities)
+	}
+	text := []rune(out.Text)
+	for _, e := range out.Entities {
+		for _, span := range e.Offsets {
+			if got := string(text[span[0]:span[1]]); got != e.Value {
+				t.Errorf("Text[%d:%d] = %q, want %q", span[0], span[1], got, e.Value)
+			}
+		}
+	}
+}
+
+func TestProcessContentFilters(t *testing.T) {
+	page := `<html><head><title>Go concurrency</title></head><body>
+		<nav><a href="/">Home</a> <a href="/blog">Blog</a></nav>
+		<article>
+			<h1>Go concurrency</h1>
+			<p>Goroutines are lightweight threads managed by the Go runtime, so programs routinely run thousands of them.</p>
+			<p>Channels connect goroutines and make a send block until a receiver is ready on an unbuffered channel.</p>
+			<p>Our office moved to a new building with a garden and a coffee bar downstairs, next to the station.</p>
+		</article>
+		<footer>Copyright Example Inc.</footer>
+	</body></html>`
+	tests := []struct {
+		name string
+		spec config.ContentFilterConfig
+		want string
+	}{
+		{"pruning", config.ContentFilterConfig{Type: config.ContentFilterPruning}, "Goroutines are lightweight"},
+		{"bm25", config.ContentFilterConfig{Type: config.ContentFilterBM25, Query: "unbuffered channel"}, "Channels connect goroutines"},
+	}
+	for _, tt := range tests {
+		for _, clean := range []bool{true, false} {
+			cfg := config.DefaultCrawlerRunConfig()
+			cfg.EnableCleanHTML = clean
+			cfg.ContentFilters = []config.ContentFilterConfig{tt.spec}
+			out, err := Process(page, "https://example.com/post", cfg)
+			if err != nil {
+				t.Fatalf("%s, clean=%v: Process: %v", tt.name, clean, err)
+			}
+			fit := string(out.FitMarkdown)
+			if !strings.Contains(fit, tt.want) {
+				t.Errorf("%s, clean=%v: FitMarkdown lacks the article:\n%s", tt.name, clean, fit)
+			}
+			if strings.Contains(fit, "Copyright") || strings.Contains(fit, "[Blog]") {
+				t.Errorf("%s, clean=%v: FitMarkdown kept navigation or footer:\n%s", tt.name, clean, fit)
+			}
+			if tt.name == "bm25" && strings.Contains(fit, "coffee") {
+				t.Errorf("bm25, clean=%v: FitMarkdown kept an unrelated block:\n%s", clean, fit)
+			}
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package extract
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xpath"
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"golang.org/x/net/html"
+)
+
+// ExtractSchema returns one object per element matching the schema's base
+// selector, built from its fields. Attribute values named href or src are
+// resolved against baseURL. An invalid selector or pattern is an error.
+func ExtractSchema(rawHTML, baseURL string, schema config.ExtractionSchema) ([]map[string]any, error) {
+	base, err := compileSelector(schema.BaseSelector, schema.SelectorType)
+	if err != nil {
+		return nil, fmt.Errorf("schema %q base selector: %w", schema.Name, err)
+	}
+	fields, err := compileFields(schema.Fields, schema.SelectorType)
+	if err != nil {
+		return nil, fmt.Errorf("schema %q: %w", schema.Name, err)
+	}
+	doc, err := html.Parse(strings.NewReader(rawHTML))
+	if err != nil {
+		return nil, err
+	}
+	pageURL, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if href, ok := findBaseHref(doc); ok {
+		if resolved, err := pageURL.Parse(href); err == nil {
+			pageURL = resolved
+		}
+	}
+
+	items := []map[string]any{}
+	for _, n := range base(doc) {
+		items = append(items, object(n, fields, pageURL))
+	}
+	return items, nil
+}
+
+// selector returns the elements matching below n.
+type selector func(n *html.Node) []*html.Node
+
+type field struct {
+	config.SchemaField
+	selector selector
+	pattern  *regexp.Regexp
+	fields   []field
+}
+
+// compileSelector compiles expr as CSS or XPath. An empty expression
+// selects the context element itself.
+func compileSelector(expr, selectorType string) (selector, error) {
+	if strings.TrimSpace(expr) == "" {
+		return func(n *html.Node) []*html.Node { return []*html.Node{n} }, nil
+	}
+	switch selectorType {
+	case "", config.SelectorTypeCSS:
+		sel, err := cascadia.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		return func(n *html.Node) []*html.Node { return cascadia.QueryAll(n, sel) }, nil
+	case config.SelectorTypeXPath:
+		compiled, err := xpath.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		return func(n *html.Node) []*html.Node { return htmlquery.QuerySelectorAll(n, compiled) }, nil
+	default:
+		return nil, fmt.Errorf("unknown selector type %q", selectorType)
+	}
+}
+
+// compileFields compiles fs, inheriting selectorType where a field sets none.
+func compileFields(fs []config.SchemaField, selectorType string) ([]field, error) {
+	out := make([]field, 0, len(fs))
+	for _, f := range fs {
+		if f.Name == "" {
+			return nil, fmt.Errorf("field without a name")
+		}
+		if f.SelectorType == "" {
+			f.SelectorType = selectorType
+		}
+		if f.Type == "" {
+			f.Type = config.FieldTypeText
+		}
+		c := field{SchemaField: f}
+		var err error
+		if c.selector, err = compileSelector(f.Selector, f.SelectorType); err != nil {
+			return nil, fmt.Errorf("field %q selector: %w", f.Name, err)
+		}
+		switch f.Type {
+		case config.FieldTypeText, config.FieldTypeHTML:
+		case config.FieldTypeAttribute:
+			if f.Attribute == "" {
+				return nil, fmt.Errorf("field %q: attribute type needs an attribute", f.Name)
+			}
+		case config.FieldTypeRegex:
+			if c.pattern, err = regexp.Compile(f.Pattern); err != nil {
+				return nil, fmt.Errorf("field %q pattern: %w", f.Name, err)
+			}
+		case config.FieldTypeNested, config.FieldTypeList:
+			if c.fields, err = compileFields(f.Fields, f.SelectorType); err != nil {
+				return nil, fmt.Errorf("field %q: %w", f.Name, err)
+			}
+		default:
+			return nil, fmt.Errorf("field %q: unknown type %q", f.Name, f.Type)
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+func object(n *html.Node, fields []field, base *url.URL) map[string]any {
+	obj := make(map[string]any, len(fields))
+	for _, f := range fields {
+		value := f.value(n, base)
+		if isEmpty(value) && f.Default != nil {
+			value = f.Default
+		}
+		obj[f.Name] = value
+	}
+	return obj
+}
+
+// value reads f from the elements its selector matches below n; nil means
+// nothing was found.
+func (f field) value(n *html.Node, base *url.URL) any {
+	matches := f.selector(n)
+	if f.Type == config.FieldTypeList {
+		values := []any{}
+		for _, m := range matches {
+			if len(f.fields) > 0 {
+				values = append(values, object(m, f.fields, base))
+			} else if v := scalar(m, f.Attribute, base); v != "" {
+				values = append(values, v)
+			}
+		}
+		return values
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+	m := matches[0]
+	switch f.Type {
+	case config.FieldTypeNested:
+		return object(m, f.fields, base)
+	case config.FieldTypeHTML:
+		return innerHTML(m)
+	case config.FieldTypeAttribute:
+		return nilIfEmpty(scalar(m, f.Attribute, base))
+	case config.FieldTypeRegex:
+		groups := f.pattern.FindStringSubmatch(scalar(m, f.Attribute, base))
+		switch {
+		case groups == nil:
+			return nil
+		case len(groups) > 1:
+			return groups[1]
+		default:
+			return groups[0]
+		}
+	default:
+		return nilIfEmpty(scalar(m, "", base))
+	}
+}
+
+// scalar returns n's attribute, resolved when it is a URL, or its
+// whitespace-collapsed text when attribute is empty.
+func scalar(n *html.Node, attribute string, base *url.URL) string {
+	if attribute == "" {
+		return strings.Join(strings.Fields(textContent(n)), " ")
+	}
+	value := strings.TrimSpace(attr(n, attribute))
+	if value != "" && (attribute == "href" || attribute == "src") {
+		if u, err := base.Parse(value); err == nil {
+			return u.String()
+		}
+	}
+	return value
+}
+
+func innerHTML(n *html.Node) any {
+	var buf bytes.Buffer
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&buf, c); err != nil {
+			return nil
+		}
+	}
+	return nilIfEmpty(strings.TrimSpace(buf.String()))
+}
+
+func nilIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case []any:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+package extract
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/techbysteve/prowl4ai/internal/model"
+	"golang.org/x/net/html"
+)
+
+const (
+	// maxSpan caps rowspan and colspan so a hostile value cannot blow up the
+	// grid.
+	maxSpan = 1000
+	// maxLayoutCellText is the average cell length above which a table
+	// without header markup is taken for page layout.
+	maxLayoutCellText = 120
+)
+
+// ExtractTables returns the page's data tables with row and column spans
+// resolved: a spanning cell's text is repeated in every slot it covers.
+// Leading header rows become Headers, with stacked header rows joined per
+// column as "Group / Column". Layout tables are skipped.
+func ExtractTables(rawHTML string) ([]model.Table, error) {
+	doc, err := html.Parse(strings.NewReader(rawHTML))
+	if err != nil {
+		return nil, err
+	}
+	var tables []model.Table
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "table" {
+			if t, ok := readTable(n); ok {
+				tables = append(tables, t)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return tables, nil
+}
+
+type tableRow struct {
+	cells  []*html.Node
+	header bool
+}
+
+func readTable(table *html.Node) (model.Table, bool) {
+	role := strings.ToLower(attr(table, "role"))
+	if role == "presentation" || role == "none" {
+		return model.Table{}, false
+	}
+
+	var (
+		caption   string
+		rows      []tableRow
+		hasHeader bool
+		nested    bool
+	)
+	for c := table.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		switch c.Data {
+		case "caption":
+			caption = collapse(textContent(c))
+		case "thead", "tbody", "tfoot":
+			for r := c.FirstChild; r != nil; r = r.NextSibling {
+				if r.Type == html.ElementNode && r.Data == "tr" {
+					rows = append(rows, readRow(r, c.Data == "thead"))
+				}
+			}
+		case "tr":
+			rows = append(rows, readRow(c, false))
+		}
+	}
+	cellCount, textLen := 0, 0
+	for _, r := range rows {
+		for _, cell := range r.cells {
+			if cell.Data == "th" {
+				hasHeader = true
+			}
+			if containsTable(cell) {
+				nested = true
+			}
+			cellCount++
+			textLen += len(collapse(textContent(cell)))
+		}
+	}
+	if nested || len(rows) < 2 || cellCount == 0 {
+		return model.Table{}, false
+	}
+
+	grid := resolveSpans(rows)
+	width := 0
+	for _, row := range grid {
+		width = max(width, len(row))
+	}
+	if width < 2 {
+		return model.Table{}, false
+	}
+	if !hasHeader && caption == "" && textLen/cellCount > maxLayoutCellText {
+		return model.Table{}, false
+	}
+
+	// Header rows are the leading thead rows or rows made only of <th>.
+	headerRows := 0
+	for headerRows < len(rows)-1 && rows[headerRows].header {
+		headerRows++
+	}
+	t := model.Table{Caption: caption}
+	if headerRows > 0 {
+		t.Headers = make([]string, width)
+		for col := range width {
+			var parts []string
+			for _, row := range grid[:headerRows] {
+				if col < len(row) && row[col] != "" && (len(parts) == 0 || parts[len(parts)-1] != row[col]) {
+					parts = append(parts, row[col])
+				}
+			}
+			t.Headers[col] = strings.Join(parts, " / ")
+		}
+	}
+	for _, row := range grid[headerRows:] {
+		padded := make([]string, width)
+		copy(padded, row)
+		empty := true
+		for _, cell := range padded {
+			if cell != "" {
+				empty = false
+				break
+			}
+		}
+		if !empty {
+			t.Rows = append(t.Rows, padded)
+		}
+	}
+	if len(t.Rows) == 0 {
+		return model.Table{}, false
+	}
+	return t, true
+}
+
+func readRow(tr *html.Node, inHead bool) tableRow {
+	row := tableRow{header: inHead}
+	allTH := true
+	for c := tr.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && (c.Data == "td" || c.Data == "th") {
+			row.cells = append(row.cells, c)
+			if c.Data != "th" {
+				allTH = false
+			}
+		}
+	}
+	if len(row.cells) > 0 && allTH {
+		row.header = true
+	}
+	return row
+}
+
+// resolveSpans lays the rows out on a grid, placing each cell in the next
+// column not covered by a rowspan from above.
+func resolveSpans(rows []tableRow) [][]string {
+	grid := make([][]string, len(rows))
+	filled := make([][]bool, len(rows))
+	set := func(r, c int, text string) {
+		for len(grid[r]) <= c {
+			grid[r] = append(grid[r], "")
+			filled[r] = append(filled[r], false)
+		}
+		grid[r][c] = text
+		filled[r][c] = true
+	}
+	for r, row := range rows {
+		col := 0
+		for _, cell := range row.cells {
+			for col < len(filled[r]) && filled[r][col] {
+				col++
+			}
+			text := collapse(textContent(cell))
+			colspan := spanAttr(cell, "colspan", 1)
+			rowspan := spanAttr(cell, "rowspan", len(rows)-r)
+			for dr := 0; dr < rowspan && r+dr < len(rows); dr++ {
+				for dc := range colspan {
+					set(r+dr, col+dc, text)
+				}
+			}
+			col += colspan
+		}
+	}
+	return grid
+}
+
+// spanAttr reads a span attribute; zero (rowspan="0") means the rest of the
+// table and is given as whole.
+func spanAttr(n *html.Node, key string, whole int) int {
+	v, err := strconv.Atoi(strings.TrimSpace(attr(n, key)))
+	switch {
+	case err != nil || v < 0:
+		return 1
+	case v == 0:
+		return max(min(whole, maxSpan), 1)
+	default:
+		return min(v, maxSpan)
+	}
+}
+
+func containsTable(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == "table" || containsTable(c) {
+			return true
+		}
+	}
+	return false
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
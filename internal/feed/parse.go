@@ -0,0 +1,374 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// Feed formats reported on Feed.Format.
+const (
+	FormatRSS  = "rss"
+	FormatAtom = "atom"
+	FormatJSON = "json"
+)
+
+// ErrNotFeed is returned by Parse for documents that are not RSS, Atom or
+// JSON Feed.
+var ErrNotFeed = errors.New("not a feed")
+
+// Feed is a parsed RSS, Atom or JSON feed.
+type Feed struct {
+	URL    string `json:"url"`
+	Format string `json:"format"`
+	Title  string `json:"title,omitempty"`
+	Link   string `json:"link,omitempty"`
+	Items  []Item `json:"items"`
+}
+
+// Item is one feed entry. URL is resolved against the feed URL.
+type Item struct {
+	ID         string    `json:"id"`
+	Title      string    `json:"title,omitempty"`
+	URL        string    `json:"url,omitempty"`
+	Published  time.Time `json:"published,omitzero"`
+	Updated    time.Time `json:"updated,omitzero"`
+	Summary    string    `json:"summary,omitempty"`
+	Author     string    `json:"author,omitempty"`
+	Categories []string  `json:"categories,omitempty"`
+}
+
+// Date returns when the item was published, or last updated when it has no
+// publication date.
+func (it Item) Date() time.Time {
+	if !it.Published.IsZero() {
+		return it.Published
+	}
+	return it.Updated
+}
+
+// Parse reads an RSS 2.0, RSS 1.0, Atom or JSON Feed document fetched from
+// feedURL.
+func Parse(data []byte, feedURL string) (Feed, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	trimmed := bytes.TrimSpace(data)
+	var (
+		f   Feed
+		err error
+	)
+	switch {
+	case len(trimmed) > 0 && trimmed[0] == '{':
+		f, err = parseJSON(trimmed)
+	case len(trimmed) > 0 && trimmed[0] == '<':
+		f, err = parseXML(trimmed)
+	default:
+		err = ErrNotFeed
+	}
+	if err != nil {
+		return Feed{}, err
+	}
+
+	f.URL = feedURL
+	base, _ := url.Parse(feedURL)
+	f.Link = resolve(base, f.Link)
+	for i := range f.Items {
+		it := &f.Items[i]
+		it.URL = resolve(base, it.URL)
+		if it.ID == "" {
+			it.ID = it.URL
+		}
+		if it.ID == "" {
+			it.ID = it.Title + "|" + it.Date().Format(time.RFC3339)
+		}
+	}
+	return f, nil
+}
+
+func resolve(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || base == nil {
+		return ref
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
+
+type jsonFeed struct {
+	Version     string `json:"version"`
+	Title       string `json:"title"`
+	HomePageURL string `json:"home_page_url"`
+	Items       []struct {
+		ID            any          `json:"id"`
+		URL           string       `json:"url"`
+		ExternalURL   string       `json:"external_url"`
+		Title         string       `json:"title"`
+		Summary       string       `json:"summary"`
+		ContentText   string       `json:"content_text"`
+		DatePublished string       `json:"date_published"`
+		DateModified  string       `json:"date_modified"`
+		Author        jsonAuthor   `json:"author"`
+		Authors       []jsonAuthor `json:"authors"`
+		Tags          []string     `json:"tags"`
+	} `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+func parseJSON(data []byte) (Feed, error) {
+	var jf jsonFeed
+	if err := json.Unmarshal(data, &jf); err != nil {
+		return Feed{}, fmt.Errorf("parse json feed: %w", err)
+	}
+	if !strings.HasPrefix(jf.Version, "https://jsonfeed.org/version/") {
+		return Feed{}, ErrNotFeed
+	}
+	f := Feed{Format: FormatJSON, Title: jf.Title, Link: jf.HomePageURL}
+	for _, ji := range jf.Items {
+		it := Item{
+			Title:      strings.TrimSpace(ji.Title),
+			URL:        ji.URL,
+			Published:  parseDate(ji.DatePublished),
+			Updated:    parseDate(ji.DateModified),
+			Summary:    strings.TrimSpace(ji.Summary),
+			Author:     ji.Author.Name,
+			Categories: ji.Tags,
+		}
+		if ji.ID != nil {
+			it.ID = strings.TrimSpace(fmt.Sprint(ji.ID))
+		}
+		if it.URL == "" {
+			it.URL = ji.ExternalURL
+		}
+		if it.Summary == "" {
+			it.Summary = strings.TrimSpace(ji.ContentText)
+		}
+		if len(ji.Authors) > 0 {
+			it.Author = ji.Authors[0].Name
+		}
+		f.Items = append(f.Items, it)
+	}
+	return f, nil
+}
+
+// xmlLink covers RSS <link>url</link> and Atom <link href="url" rel="..."/>.
+type xmlLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Text string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string    `xml:"title"`
+	Links       []xmlLink `xml:"link"`
+	GUID        string    `xml:"guid"`
+	About       string    `xml:"about,attr"`
+	PubDate     string    `xml:"pubDate"`
+	Date        string    `xml:"date"`
+	Description string    `xml:"description"`
+	Author      string    `xml:"author"`
+	Creator     string    `xml:"creator"`
+	Categories  []string  `xml:"category"`
+}
+
+type rssChannel struct {
+	Title string    `xml:"title"`
+	Links []xmlLink `xml:"link"`
+	Items []rssItem `xml:"item"`
+}
+
+// rssDoc covers RSS 2.0, where items sit in the channel, and RSS 1.0, where
+// they are siblings of it.
+type rssDoc struct {
+	Channel rssChannel `xml:"channel"`
+	Items   []rssItem  `xml:"item"`
+}
+
+type atomEntry struct {
+	ID        string    `xml:"id"`
+	Title     string    `xml:"title"`
+	Links     []xmlLink `xml:"link"`
+	Published string    `xml:"published"`
+	Updated   string    `xml:"updated"`
+	Summary   string    `xml:"summary"`
+	Content   string    `xml:"content"`
+	Authors   []struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Categories []struct {
+		Term string `xml:"term,attr"`
+	} `xml:"category"`
+}
+
+type atomFeed struct {
+	Title   string      `xml:"title"`
+	Links   []xmlLink   `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+func parseXML(data []byte) (Feed, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.CharsetReader = charset.NewReaderLabel
+	dec.Strict = false
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return Feed{}, fmt.Errorf("parse feed: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "rss", "RDF":
+			var doc rssDoc
+			if err := dec.DecodeElement(&doc, &start); err != nil {
+				return Feed{}, fmt.Errorf("parse rss feed: %w", err)
+			}
+			return rssFeed(doc), nil
+		case "feed":
+			var doc atomFeed
+			if err := dec.DecodeElement(&doc, &start); err != nil {
+				return Feed{}, fmt.Errorf("parse atom feed: %w", err)
+			}
+			return atomToFeed(doc), nil
+		default:
+			return Feed{}, ErrNotFeed
+		}
+	}
+}
+
+func rssFeed(doc rssDoc) Feed {
+	f := Feed{
+		Format: FormatRSS,
+		Title:  strings.TrimSpace(doc.Channel.Title),
+		Link:   rssLink(doc.Channel.Links),
+	}
+	for _, ri := range append(doc.Channel.Items, doc.Items...) {
+		it := Item{
+			ID:         strings.TrimSpace(ri.GUID),
+			Title:      strings.TrimSpace(ri.Title),
+			URL:        rssLink(ri.Links),
+			Published:  parseDate(ri.PubDate),
+			Summary:    strings.TrimSpace(ri.Description),
+			Author:     strings.TrimSpace(ri.Creator),
+			Categories: trimAll(ri.Categories),
+		}
+		if it.ID == "" {
+			it.ID = strings.TrimSpace(ri.About)
+		}
+		if it.Published.IsZero() {
+			it.Published = parseDate(ri.Date)
+		}
+		if it.Author == "" {
+			it.Author = strings.TrimSpace(ri.Author)
+		}
+		f.Items = append(f.Items, it)
+	}
+	return f
+}
+
+// rssLink returns the first text link, skipping atom:link self references.
+func rssLink(links []xmlLink) string {
+	for _, l := range links {
+		if text := strings.TrimSpace(l.Text); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+func atomToFeed(doc atomFeed) Feed {
+	f := Feed{
+		Format: FormatAtom,
+		Title:  strings.TrimSpace(doc.Title),
+		Link:   atomLink(doc.Links),
+	}
+	for _, e := range doc.Entries {
+		it := Item{
+			ID:        strings.TrimSpace(e.ID),
+			Title:     strings.TrimSpace(e.Title),
+			URL:       atomLink(e.Links),
+			Published: parseDate(e.Published),
+			Updated:   parseDate(e.Updated),
+			Summary:   strings.TrimSpace(e.Summary),
+		}
+		if it.Summary == "" {
+			it.Summary = strings.TrimSpace(e.Content)
+		}
+		if len(e.Authors) > 0 {
+			it.Author = strings.TrimSpace(e.Authors[0].Name)
+		}
+		for _, c := range e.Categories {
+			if term := strings.TrimSpace(c.Term); term != "" {
+				it.Categories = append(it.Categories, term)
+			}
+		}
+		f.Items = append(f.Items, it)
+	}
+	return f
+}
+
+// atomLink returns the rel="alternate" link, which is also the default rel.
+func atomLink(links []xmlLink) string {
+	for _, l := range links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return strings.TrimSpace(l.Href)
+		}
+	}
+	return ""
+}
+
+func trimAll(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// dateLayouts are the RFC 822 variants RSS uses in practice and the RFC 3339
+// forms of Atom and JSON Feed.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	"Mon, 02 Jan 2006 15:04 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	time.RFC822Z,
+	time.RFC822,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parseDate returns the zero time for missing or unrecognized dates.
+func parseDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
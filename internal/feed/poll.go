@@ -0,0 +1,197 @@
+package feed
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/extract"
+	"github.com/techbysteve/prowl4ai/internal/model"
+	"github.com/techbysteve/prowl4ai/internal/prowler"
+)
+
+const (
+	maxFeedSize  = 20 << 20
+	fetchTimeout = 30 * time.Second
+)
+
+// Client fetches feeds over plain HTTP.
+type Client struct {
+	userAgent string
+	client    *http.Client
+}
+
+// NewClient returns a client that sends userAgent with its requests.
+func NewClient(userAgent string) *Client {
+	return &Client{userAgent: userAgent, client: &http.Client{Timeout: fetchTimeout}}
+}
+
+// Fetch downloads and parses the feed at rawURL. When rawURL is an HTML page,
+// the feeds it advertises with <link rel="alternate"> are tried in order and
+// the first that fetches and parses is returned.
+func (c *Client) Fetch(ctx context.Context, rawURL string) (Feed, error) {
+	data, finalURL, err := c.get(ctx, rawURL)
+	if err != nil {
+		return Feed{}, err
+	}
+	f, err := Parse(data, finalURL)
+	if !errors.Is(err, ErrNotFeed) {
+		return f, err
+	}
+	links, linkErr := extract.ExtractFeedLinks(string(data), finalURL)
+	if linkErr != nil || len(links) == 0 {
+		return Feed{}, fmt.Errorf("%s: %w and advertises no feed", rawURL, ErrNotFeed)
+	}
+	var errs []error
+	for _, link := range links {
+		data, finalURL, err := c.get(ctx, link.URL)
+		if err == nil {
+			var f Feed
+			if f, err = Parse(data, finalURL); err == nil {
+				return f, nil
+			}
+		}
+		if ctx.Err() != nil {
+			return Feed{}, ctx.Err()
+		}
+		errs = append(errs, err)
+	}
+	return Feed{}, fmt.Errorf("%s: no advertised feed could be read: %w", rawURL, errors.Join(errs...))
+}
+
+func (c *Client) get(ctx context.Context, rawURL string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetch feed %s: %w", rawURL, err)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/feed+json, application/xml;q=0.9, text/html;q=0.8, */*;q=0.5")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetch feed %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", fmt.Errorf("fetch feed %s: status %d", rawURL, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return nil, "", fmt.Errorf("fetch feed %s: %w", rawURL, err)
+	}
+	return bytes.TrimSpace(data), resp.Request.URL.String(), nil
+}
+
+// ItemResult is the crawl of one new feed item.
+type ItemResult struct {
+	Feed   string            `json:"feed"`
+	Item   Item              `json:"item"`
+	Result model.CrawlResult `json:"result"`
+}
+
+// Poller crawls the items a feed gained since its stored watermark.
+type Poller struct {
+	client  *Client
+	service *prowler.Service
+	store   *WatermarkStore
+}
+
+// NewPoller returns a poller that fetches feeds with client, crawls items
+// through service and keeps watermarks in store.
+func NewPoller(client *Client, service *prowler.Service, store *WatermarkStore) *Poller {
+	return &Poller{client: client, service: service, store: store}
+}
+
+// Poll fetches the feed at feedURL, crawls its new items oldest first and
+// calls emit with each, then advances and saves the feed's watermark. Items
+// whose crawl fails stay new and are retried on the next poll, unless the
+// failure is permanent (see permanentFailure). Poll returns
+// the number of items crawled and stops early if emit returns an error.
+func (p *Poller) Poll(ctx context.Context, feedURL string, runCfg config.CrawlerRunConfig, emit func(ItemResult) error) (int, error) {
+	f, err := p.client.Fetch(ctx, feedURL)
+	if err != nil {
+		return 0, err
+	}
+	mark := p.store.Get(f.URL)
+
+	var fresh []Item
+	for _, it := range f.Items {
+		if it.URL != "" && mark.IsNew(it) {
+			fresh = append(fresh, it)
+		}
+	}
+	// Oldest first; undated items keep feed order after the dated ones.
+	slices.SortStableFunc(fresh, func(a, b Item) int {
+		da, db := a.Date(), b.Date()
+		switch {
+		case da.IsZero() || db.IsZero():
+			return compareBool(da.IsZero(), db.IsZero())
+		default:
+			return da.Compare(db)
+		}
+	})
+
+	pending := map[string]bool{}
+	for _, it := range fresh {
+		pending[it.ID] = true
+	}
+	crawled := 0
+	var stopErr error
+	for _, it := range fresh {
+		if stopErr = ctx.Err(); stopErr != nil {
+			break
+		}
+		result, _ := p.service.Run(ctx, it.URL, runCfg)
+		if stopErr = ctx.Err(); stopErr != nil {
+			break
+		}
+		crawled++
+		if stopErr = emit(ItemResult{Feed: f.URL, Item: it, Result: result}); stopErr != nil {
+			break
+		}
+		if result.Success || permanentFailure(result) {
+			delete(pending, it.ID)
+		}
+	}
+
+	p.store.Set(f.URL, mark.advance(f, func(it Item) bool { return !pending[it.ID] }))
+	if err := p.store.Save(); err != nil {
+		return crawled, errors.Join(stopErr, err)
+	}
+	return crawled, stopErr
+}
+
+// permanentFailure reports whether a failed crawl would fail the same way
+// on every poll: robots.txt forbids the URL or the server answered with a
+// client error other than a timeout or rate limit. Retrying such items would
+// hold the watermark back forever.
+func permanentFailure(result model.CrawlResult) bool {
+	if result.ErrorCode == model.ErrorCodeRobotsDisallowed {
+		return true
+	}
+	switch status := result.StatusCode; {
+	case status == http.StatusRequestTimeout, status == http.StatusTooManyRequests:
+		return false
+	default:
+		return status >= 400 && status < 500
+	}
+}
+
+// compareBool orders false before true.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
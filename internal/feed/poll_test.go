@@ -0,0 +1,72 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/techbysteve/prowl4ai/internal/browser"
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/prowler"
+)
+
+// itemAdapter answers each item URL by its last path segment.
+type itemAdapter struct{}
+
+func (itemAdapter) Start(context.Context) error { return nil }
+func (itemAdapter) Close(context.Context) error { return nil }
+
+func (itemAdapter) FetchHTML(_ context.Context, url string, _ config.CrawlerRunConfig) (browser.FetchResult, error) {
+	switch url[strings.LastIndex(url, "/")+1:] {
+	case "gone":
+		return browser.FetchResult{StatusCode: http.StatusGone, ContentType: "application/octet-stream"}, nil
+	case "busy":
+		return browser.FetchResult{StatusCode: http.StatusTooManyRequests, ContentType: "application/octet-stream"}, nil
+	case "down":
+		return browser.FetchResult{}, errors.New("connection refused")
+	}
+	return browser.FetchResult{HTML: "<html><body><p>ok</p></body></html>", StatusCode: http.StatusOK, ContentType: "text/html"}, nil
+}
+
+func TestPollRetriesOnlyTransientFailures(t *testing.T) {
+	const feedJSON = `{"version": "https://jsonfeed.org/version/1.1", "title": "Test", "items": [
+		{"id": "ok", "url": "https://example.com/ok", "date_published": "2026-01-01T00:00:00Z"},
+		{"id": "gone", "url": "https://example.com/gone", "date_published": "2026-01-02T00:00:00Z"},
+		{"id": "busy", "url": "https://example.com/busy", "date_published": "2026-01-03T00:00:00Z"},
+		{"id": "down", "url": "https://example.com/down", "date_published": "2026-01-04T00:00:00Z"},
+		{"id": "new", "url": "https://example.com/new", "date_published": "2026-01-05T00:00:00Z"}
+	]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/feed+json")
+		_, _ = w.Write([]byte(feedJSON))
+	}))
+	defer server.Close()
+
+	store, err := OpenWatermarks(filepath.Join(t.TempDir(), "watermarks.json"))
+	if err != nil {
+		t.Fatalf("OpenWatermarks: %v", err)
+	}
+	poller := NewPoller(NewClient(""), prowler.NewService(itemAdapter{}), store)
+	poll := func() []string {
+		var ids []string
+		_, err := poller.Poll(context.Background(), server.URL, config.DefaultCrawlerRunConfig(), func(r ItemResult) error {
+			ids = append(ids, r.Item.ID)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Poll: %v", err)
+		}
+		return ids
+	}
+
+	if got := strings.Join(poll(), ","); got != "ok,gone,busy,down,new" {
+		t.Fatalf("first poll crawled %s", got)
+	}
+	if got := strings.Join(poll(), ","); got != "busy,down" {
+		t.Errorf("second poll crawled %s, want only the transient failures", got)
+	}
+}
@@ -0,0 +1,122 @@
+package feed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/techbysteve/prowl4ai/internal/cache"
+	"github.com/techbysteve/prowl4ai/internal/fsutil"
+)
+
+// Watermark records how far a feed has been crawled: the newest item date
+// handled and the IDs of the items already handled.
+type Watermark struct {
+	Newest    time.Time `json:"newest,omitzero"`
+	Seen      []string  `json:"seen,omitempty"`
+	CheckedAt time.Time `json:"checked_at,omitzero"`
+}
+
+// IsNew reports whether it has not been handled: its ID is unseen and it is
+// not older than the watermark. Undated items are judged by ID alone.
+func (w Watermark) IsNew(it Item) bool {
+	for _, id := range w.Seen {
+		if id == it.ID {
+			return false
+		}
+	}
+	date := it.Date()
+	return date.IsZero() || !date.Before(w.Newest)
+}
+
+// advance returns the watermark after polling f, where handled reports the
+// items that were crawled or already known. The newest date stops short of
+// any unhandled item so it is retried on the next poll.
+func (w Watermark) advance(f Feed, handled func(Item) bool) Watermark {
+	next := Watermark{Newest: w.Newest, CheckedAt: time.Now().UTC()}
+	var retry time.Time
+	for _, it := range f.Items {
+		if !handled(it) {
+			if d := it.Date(); !d.IsZero() && (retry.IsZero() || d.Before(retry)) {
+				retry = d
+			}
+			continue
+		}
+		next.Seen = append(next.Seen, it.ID)
+		if d := it.Date(); d.After(next.Newest) {
+			next.Newest = d
+		}
+	}
+	if !retry.IsZero() && retry.Before(next.Newest) {
+		next.Newest = retry
+	}
+	return next
+}
+
+// WatermarkStore keeps watermarks by feed URL in a JSON file. It is safe for
+// concurrent use.
+type WatermarkStore struct {
+	path  string
+	mu    sync.Mutex
+	marks map[string]Watermark
+}
+
+// DefaultWatermarkPath is the watermark file inside the default cache
+// directory.
+func DefaultWatermarkPath() string {
+	return filepath.Join(cache.DefaultDir(), "feeds", "watermarks.json")
+}
+
+// OpenWatermarks loads the watermarks saved at path, or DefaultWatermarkPath
+// when path is empty. A missing file starts an empty store.
+func OpenWatermarks(path string) (*WatermarkStore, error) {
+	if path == "" {
+		path = DefaultWatermarkPath()
+	}
+	s := &WatermarkStore{path: path, marks: map[string]Watermark{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read feed watermarks: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.marks); err != nil {
+		return nil, fmt.Errorf("parse feed watermarks %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Get returns the watermark of feedURL; a feed never polled has the zero
+// watermark, so all of its items are new.
+func (s *WatermarkStore) Get(feedURL string) Watermark {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.marks[feedURL]
+}
+
+// Set replaces the watermark of feedURL in memory; Save persists it.
+func (s *WatermarkStore) Set(feedURL string, w Watermark) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.marks[feedURL] = w
+}
+
+// Save writes the store to its file atomically.
+func (s *WatermarkStore) Save() error {
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s.marks, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encode feed watermarks: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("write feed watermarks: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package mediatype
+
+import (
+	"mime"
+	"strings"
+)
+
+// Of returns the lower-cased MIME type of a Content-Type header value,
+// without parameters such as charset.
+func Of(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, _, _ = strings.Cut(contentType, ";")
+	}
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// IsHTML reports whether contentType is rendered and extracted as HTML. An
+// empty content type counts as HTML since browsers render it that way.
+func IsHTML(contentType string) bool {
+	switch Of(contentType) {
+	case "", "text/html", "application/xhtml+xml":
+		return true
+	}
+	return false
+}
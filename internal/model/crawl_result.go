@@ -1,17 +1,130 @@
 package model
 
+import (
+	"encoding/csv"
+	"io"
+)
+
 type Markdown string
 
+// Cache statuses reported on CrawlResult.CacheStatus.
+const (
+	CacheStatusHit    = "hit"
+	CacheStatusMiss   = "miss"
+	CacheStatusStored = "stored"
+	// CacheStatusNotModified means a conditional refetch returned 304 and the
+	// cached result was served.
+	CacheStatusNotModified = "not_modified"
+)
+
+// Error codes reported on CrawlResult.ErrorCode.
+const (
+	// ErrorCodeRobotsDisallowed means robots.txt forbids fetching the URL, so
+	// no request was made.
+	ErrorCodeRobotsDisallowed = "robots_disallowed"
+)
+
+// Built-in entity types reported on Entity.Type.
+const (
+	EntityEmail      = "email"
+	EntityPhone      = "phone"
+	EntityURL        = "url"
+	EntityIPv4       = "ipv4"
+	EntityIPv6       = "ipv6"
+	EntityDate       = "date"
+	EntityPrice      = "price"
+	EntityPostalCode = "postal_code"
+	EntityUUID       = "uuid"
+)
+
 type CrawlResult struct {
 	URL             string         `json:"url"`
 	HTML            string         `json:"html,omitempty"`
 	CleanedHTML     string         `json:"cleaned_html,omitempty"`
 	Success         bool           `json:"success"`
 	Markdown        Markdown       `json:"markdown,omitempty"`
+	FitMarkdown     Markdown       `json:"fit_markdown,omitempty"`
 	Metadata        map[string]any `json:"metadata,omitempty"`
 	ErrorMessage    string         `json:"error_message,omitempty"`
+	ErrorCode       string         `json:"error_code,omitempty"`
 	SessionID       string         `json:"session_id,omitempty"`
 	ResponseHeaders map[string]any `json:"response_headers,omitempty"`
 	StatusCode      int            `json:"status_code,omitempty"`
 	RedirectedURL   string         `json:"redirected_url,omitempty"`
+	ContentType     string         `json:"content_type,omitempty"`
+	Proxy           string         `json:"proxy,omitempty"`
+	UserAgent       string         `json:"user_agent,omitempty"`
+	Downloads       []Download     `json:"downloads,omitempty"`
+	CacheStatus     string         `json:"cache_status,omitempty"`
+	ContentHash     string         `json:"content_hash,omitempty"`
+	SimHash         string         `json:"simhash,omitempty"`
+	Links           Links          `json:"links,omitzero"`
+	// ExtractedContent holds the objects scraped with the run's extraction
+	// schema.
+	ExtractedContent []map[string]any `json:"extracted_content,omitempty"`
+	// Entities lists the pattern matches found in Text, the page text, which
+	// is only set when entity extraction runs.
+	Entities []Entity `json:"entities,omitempty"`
+	Text     string   `json:"text,omitempty"`
+	Tables   []Table  `json:"tables,omitempty"`
+	// Depth and ParentURL place deep-crawl results in the link graph: seeds
+	// have depth 0 and no parent.
+	Depth     int    `json:"depth,omitempty"`
+	ParentURL string `json:"parent_url,omitempty"`
+}
+
+// Link is a hyperlink found on a crawled page.
+type Link struct {
+	Href  string `json:"href"`
+	Text  string `json:"text,omitempty"`
+	Title string `json:"title,omitempty"`
+	Rel   string `json:"rel,omitempty"`
+}
+
+// Links groups a page's hyperlinks by whether they stay on the page's host.
+type Links struct {
+	Internal []Link `json:"internal,omitempty"`
+	External []Link `json:"external,omitempty"`
+}
+
+// Entity is a distinct value matched by a built-in or custom pattern.
+// Offsets holds the [start, end) character offsets of each occurrence in
+// CrawlResult.Text.
+type Entity struct {
+	Type    string   `json:"type"`
+	Value   string   `json:"value"`
+	Offsets [][2]int `json:"offsets"`
+}
+
+// Table is a data table with spans resolved, so every row has one cell per
+// column. Headers is empty when the table has no header rows.
+type Table struct {
+	Caption string     `json:"caption,omitempty"`
+	Headers []string   `json:"headers,omitempty"`
+	Rows    [][]string `json:"rows"`
+}
+
+// WriteCSV writes the table as CSV, headers first.
+func (t Table) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if len(t.Headers) > 0 {
+		if err := cw.Write(t.Headers); err != nil {
+			return err
+		}
+	}
+	if err := cw.WriteAll(t.Rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// Download describes a file saved while crawling a page.
+type Download struct {
+	URL               string `json:"url"`
+	SuggestedFilename string `json:"suggested_filename"`
+	Path              string `json:"path,omitempty"`
+	Size              int64  `json:"size,omitempty"`
+	MimeType          string `json:"mime_type,omitempty"`
+	SHA256            string `json:"sha256,omitempty"`
+	Error             string `json:"error,omitempty"`
 }
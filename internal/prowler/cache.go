@@ -0,0 +1,148 @@
+package prowler
+
+import (
+	"context"
+	"time"
+
+	"github.com/techbysteve/prowl4ai/internal/browser"
+	"github.com/techbysteve/prowl4ai/internal/cache"
+	"github.com/techbysteve/prowl4ai/internal/change"
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/model"
+	"github.com/techbysteve/prowl4ai/internal/stderrors"
+)
+
+// SetCache sets the store used by runs whose cache mode reads or writes.
+// Without one, a disk store in cache.DefaultDir is opened on first use.
+func (s *Service) SetCache(store cache.Store) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache = store
+}
+
+// Cached returns the cached result for url under cfg without fetching. The
+// boolean is false when nothing is cached.
+func (s *Service) Cached(url string, cfg config.CrawlerRunConfig) (model.CrawlResult, bool, error) {
+	key, err := s.cacheKey(url, cfg)
+	if err != nil {
+		return model.CrawlResult{}, false, err
+	}
+	store, err := s.cacheStore()
+	if err != nil {
+		return model.CrawlResult{}, false, err
+	}
+	entry, ok, err := store.Get(key)
+	if err != nil || !ok {
+		return model.CrawlResult{}, false, err
+	}
+	result, err := s.runCached(url, entry, store, false, model.CacheStatusHit, cfg)
+	return result, true, err
+}
+
+// CompareWithCache crawls url fresh, storing the result in the cache, and
+// diffs it against the version cached before. It fails with
+// stderrors.ErrNotCached when there is no earlier version.
+func (s *Service) CompareWithCache(ctx context.Context, url string, cfg config.CrawlerRunConfig) (change.Report, model.CrawlResult, error) {
+	cached, ok, err := s.Cached(url, cfg)
+	if err != nil {
+		return change.Report{}, model.CrawlResult{}, err
+	}
+	if !ok {
+		return change.Report{}, model.CrawlResult{}, stderrors.ErrNotCached
+	}
+
+	cfg.CacheMode = config.CacheModeWriteOnly
+	fresh, err := s.Run(ctx, url, cfg)
+	if err != nil {
+		return change.Report{}, fresh, err
+	}
+	return change.Diff(cached, fresh), fresh, nil
+}
+
+// cacheKey keys url under cfg and the adapter's fetch profile, if any.
+func (s *Service) cacheKey(url string, cfg config.CrawlerRunConfig) (string, error) {
+	profile := ""
+	if p, ok := s.browser.(browser.FetchProfiler); ok {
+		profile = p.FetchProfile()
+	}
+	return cache.Key(url, cfg, profile)
+}
+
+func (s *Service) cacheStore() (cache.Store, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cache == nil {
+		store, err := cache.OpenDisk("")
+		if err != nil {
+			return nil, err
+		}
+		s.cache = store
+	}
+	return s.cache, nil
+}
+
+// runCached serves a run from a cache entry, reporting status as the cache
+// status. The stored result is reused when it was extracted with the same
+// settings; otherwise extraction is re-run on the cached response and, when
+// writable, the entry is updated.
+func (s *Service) runCached(url string, entry cache.Entry, store cache.Store, writable bool, status string, cfg config.CrawlerRunConfig) (model.CrawlResult, error) {
+	extractKey, err := cache.ExtractKey(cfg)
+	if err != nil {
+		return failedResult(url, err)
+	}
+	if entry.Result != nil && extractKey != "" && entry.ExtractKey == extractKey {
+		result := *entry.Result
+		result.URL = url
+		result.CacheStatus = status
+		return result, nil
+	}
+
+	result, err := buildResult(url, cachedFetchResult(entry), cfg)
+	result.CacheStatus = status
+	if err == nil && writable {
+		entry.ExtractKey = extractKey
+		entry.Result = &result
+		_ = store.Put(entry)
+	}
+	return result, err
+}
+
+// cacheableStatus reports whether a response with status may be cached: any
+// 2xx, or no status at all, as for navigations the browser turned into
+// downloads.
+func cacheableStatus(status int) bool {
+	return status == 0 || status >= 200 && status < 300
+}
+
+func newCacheEntry(key, url string, fetchResult browser.FetchResult) cache.Entry {
+	entry := cache.Entry{
+		Key:             key,
+		URL:             url,
+		FetchedAt:       time.Now().UTC(),
+		StatusCode:      fetchResult.StatusCode,
+		RedirectedURL:   fetchResult.RedirectedURL,
+		ResponseHeaders: fetchResult.ResponseHeaders,
+		ContentType:     fetchResult.ContentType,
+		HTML:            fetchResult.HTML,
+		Body:            fetchResult.Body,
+		Proxy:           fetchResult.Proxy,
+		UserAgent:       fetchResult.UserAgent,
+		Downloads:       fetchResult.Downloads,
+	}
+	entry.SetValidators(fetchResult.ResponseHeaders)
+	return entry
+}
+
+func cachedFetchResult(entry cache.Entry) browser.FetchResult {
+	return browser.FetchResult{
+		HTML:            entry.HTML,
+		StatusCode:      entry.StatusCode,
+		RedirectedURL:   entry.RedirectedURL,
+		ResponseHeaders: entry.ResponseHeaders,
+		ContentType:     entry.ContentType,
+		Body:            entry.Body,
+		Proxy:           entry.Proxy,
+		UserAgent:       entry.UserAgent,
+		Downloads:       entry.Downloads,
+	}
+}
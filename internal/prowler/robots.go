@@ -0,0 +1,48 @@
+package prowler
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/robots"
+	"github.com/techbysteve/prowl4ai/internal/stderrors"
+)
+
+// SetRobots sets the checker used by runs with CheckRobotsTxt. Without one,
+// a checker for config.DefaultUserAgent is created on first use.
+func (s *Service) SetRobots(checker *robots.Checker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.robots = checker
+}
+
+func (s *Service) robotsChecker() *robots.Checker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.robots == nil {
+		s.robots = robots.NewChecker(config.DefaultUserAgent)
+	}
+	return s.robots
+}
+
+// checkRobots returns ErrRobotsDisallowed when robots.txt forbids fetching
+// rawURL, and otherwise waits out the host's Crawl-delay.
+func (s *Service) checkRobots(ctx context.Context, rawURL string) error {
+	verdict, err := s.robotsChecker().Check(ctx, rawURL)
+	if err != nil {
+		return err
+	}
+	if !verdict.Allowed {
+		if verdict.Reason != "" {
+			return fmt.Errorf("%w: %s (%s)", stderrors.ErrRobotsDisallowed, rawURL, verdict.Reason)
+		}
+		return fmt.Errorf("%w: %s", stderrors.ErrRobotsDisallowed, rawURL)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %w", stderrors.ErrInvalidURL, err)
+	}
+	return s.limiter.Wait(ctx, u.Host, verdict.CrawlDelay)
+}
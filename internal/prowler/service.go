@@ -2,12 +2,21 @@ package prowler
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
 	"sync"
+	"time"
 
 	"github.com/techbysteve/prowl4ai/internal/browser"
+	"github.com/techbysteve/prowl4ai/internal/cache"
+	"github.com/techbysteve/prowl4ai/internal/change"
 	"github.com/techbysteve/prowl4ai/internal/config"
 	"github.com/techbysteve/prowl4ai/internal/extract"
 	"github.com/techbysteve/prowl4ai/internal/model"
+	"github.com/techbysteve/prowl4ai/internal/ratelimit"
+	"github.com/techbysteve/prowl4ai/internal/robots"
 	"github.com/techbysteve/prowl4ai/internal/stderrors"
 )
 
@@ -15,11 +24,15 @@ type Service struct {
 	browser browser.Adapter
 	mu      sync.Mutex
 	ready   bool
+	cache   cache.Store
+	robots  *robots.Checker
+	limiter *ratelimit.HostLimiter
 }
 
 func NewService(adapter browser.Adapter) *Service {
 	return &Service{
 		browser: adapter,
+		limiter: ratelimit.NewHostLimiter(),
 	}
 }
 
@@ -95,15 +108,109 @@ func (s *Service) Close(ctx context.Context) error {
 }
 
 func (s *Service) Run(ctx context.Context, url string, cfg config.CrawlerRunConfig) (model.CrawlResult, error) {
-	fetchResult, err := s.FetchHTML(ctx, url, cfg)
+	mode := cfg.CacheMode
+	if mode == "" {
+		mode = config.DefaultCacheMode
+	}
+	if !cache.ValidMode(mode) {
+		return failedResult(url, fmt.Errorf("unsupported cache mode: %s", mode))
+	}
+
+	var (
+		store      cache.Store
+		key        string
+		extractKey string
+	)
+	if cache.Readable(mode) || cache.Writable(mode) {
+		var err error
+		if key, err = s.cacheKey(url, cfg); err != nil {
+			return failedResult(url, err)
+		}
+		if extractKey, err = cache.ExtractKey(cfg); err != nil {
+			return failedResult(url, err)
+		}
+		if store, err = s.cacheStore(); err != nil {
+			return failedResult(url, err)
+		}
+	}
+
+	// Entries past CacheMaxAgeMs are revalidated with a conditional request
+	// instead of served directly. Write-only mode never reads the store, so
+	// it always fetches in full.
+	var cached *cache.Entry
+	if cache.Readable(mode) {
+		if entry, ok, err := store.Get(key); err == nil && ok {
+			maxAge := time.Duration(cfg.CacheMaxAgeMs) * time.Millisecond
+			if entry.Fresh(maxAge) {
+				return s.runCached(url, entry, store, cache.Writable(mode), model.CacheStatusHit, cfg)
+			}
+			cached = &entry
+		}
+	}
+
+	fetchCfg := cfg
+	if cached != nil {
+		if conditional := cached.ConditionalHeaders(); conditional != nil {
+			fetchCfg.Headers = make(map[string]string, len(cfg.Headers)+len(conditional))
+			maps.Copy(fetchCfg.Headers, cfg.Headers)
+			maps.Copy(fetchCfg.Headers, conditional)
+		}
+	}
+
+	// Cache hits above make no request, so robots.txt only gates the fetch.
+	if cfg.CheckRobotsTxt {
+		if err := s.checkRobots(ctx, url); err != nil {
+			return failedResult(url, err)
+		}
+	}
+	fetchResult, err := s.FetchHTML(ctx, url, fetchCfg)
 	if err != nil {
-		return model.CrawlResult{
-			URL:          url,
-			Success:      false,
-			ErrorMessage: err.Error(),
-		}, err
+		return failedResult(url, err)
+	}
+	if cached != nil && fetchResult.StatusCode == http.StatusNotModified {
+		if cache.Writable(mode) {
+			cached.FetchedAt = time.Now().UTC()
+			_ = store.Put(*cached)
+		}
+		return s.runCached(url, *cached, store, cache.Writable(mode), model.CacheStatusNotModified, cfg)
+	}
+
+	result, err := buildResult(url, fetchResult, cfg)
+	if store != nil {
+		result.CacheStatus = model.CacheStatusMiss
+	}
+	// Failed fetches and error responses are not stored: without a max age a
+	// cached 503 would otherwise be served for good.
+	if cache.Writable(mode) && err == nil && cacheableStatus(fetchResult.StatusCode) {
+		entry := newCacheEntry(key, url, fetchResult)
+		entry.ExtractKey = extractKey
+		entry.Result = &result
+		if store.Put(entry) == nil {
+			result.CacheStatus = model.CacheStatusStored
+		}
+	}
+	return result, err
+}
+
+func failedResult(url string, err error) (model.CrawlResult, error) {
+	return model.CrawlResult{
+		URL:          url,
+		Success:      false,
+		ErrorMessage: err.Error(),
+		ErrorCode:    errorCode(err),
+	}, err
+}
+
+// errorCode maps errors that callers need to tell apart to a stable code.
+func errorCode(err error) string {
+	if errors.Is(err, stderrors.ErrRobotsDisallowed) {
+		return model.ErrorCodeRobotsDisallowed
 	}
+	return ""
+}
 
+// buildResult runs extraction on a fetch and assembles the crawl result.
+func buildResult(url string, fetchResult browser.FetchResult, cfg config.CrawlerRunConfig) (model.CrawlResult, error) {
 	headers := make(map[string]any, len(fetchResult.ResponseHeaders))
 	for k, v := range fetchResult.ResponseHeaders {
 		headers[k] = v
@@ -114,7 +221,7 @@ func (s *Service) Run(ctx context.Context, url string, cfg config.CrawlerRunConf
 		baseURL = url
 	}
 
-	extractOut, extractErr := extract.Process(fetchResult.HTML, baseURL, cfg)
+	extractOut, extractErr := extractContent(fetchResult, baseURL, cfg)
 	if extractErr != nil {
 		return model.CrawlResult{
 			URL:             url,
@@ -124,18 +231,55 @@ func (s *Service) Run(ctx context.Context, url string, cfg config.CrawlerRunConf
 			ResponseHeaders: headers,
 			StatusCode:      fetchResult.StatusCode,
 			RedirectedURL:   fetchResult.RedirectedURL,
+			ContentType:     fetchResult.ContentType,
+			Proxy:           fetchResult.Proxy,
+			UserAgent:       fetchResult.UserAgent,
+			Downloads:       fetchResult.Downloads,
 		}, extractErr
 	}
 
-	return model.CrawlResult{
-		URL:             url,
-		HTML:            fetchResult.HTML,
-		CleanedHTML:     extractOut.CleanedHTML,
-		Success:         true,
-		Markdown:        model.Markdown(extractOut.Markdown),
-		Metadata:        extractOut.Metadata,
-		ResponseHeaders: headers,
-		StatusCode:      fetchResult.StatusCode,
-		RedirectedURL:   fetchResult.RedirectedURL,
-	}, nil
+	result := model.CrawlResult{
+		URL:              url,
+		HTML:             fetchResult.HTML,
+		CleanedHTML:      extractOut.CleanedHTML,
+		Success:          true,
+		Markdown:         model.Markdown(extractOut.Markdown),
+		FitMarkdown:      model.Markdown(extractOut.FitMarkdown),
+		Metadata:         extractOut.Metadata,
+		Links:            extractOut.Links,
+		ExtractedContent: extractOut.ExtractedContent,
+		Entities:         extractOut.Entities,
+		Text:             extractOut.Text,
+		Tables:           extractOut.Tables,
+		ResponseHeaders:  headers,
+		StatusCode:       fetchResult.StatusCode,
+		RedirectedURL:    fetchResult.RedirectedURL,
+		ContentType:      fetchResult.ContentType,
+		Proxy:            fetchResult.Proxy,
+		UserAgent:        fetchResult.UserAgent,
+		Downloads:        fetchResult.Downloads,
+	}
+	if result.Markdown != "" {
+		result.ContentHash, result.SimHash = change.Fingerprint(string(result.Markdown))
+	}
+	return result, nil
+}
+
+// extractContent runs the extraction pipeline matching the fetched content.
+// Pages that only produced downloads of types the pipeline cannot read are
+// returned without extracted content rather than failing the crawl.
+func extractContent(fetchResult browser.FetchResult, baseURL string, cfg config.CrawlerRunConfig) (extract.Output, error) {
+	downloadOnly := len(fetchResult.Downloads) > 0 && fetchResult.HTML == ""
+	if downloadOnly && fetchResult.Body == nil {
+		return extract.Output{Metadata: map[string]any{}}, nil
+	}
+	if fetchResult.Body == nil {
+		return extract.Process(fetchResult.HTML, baseURL, cfg)
+	}
+
+	out, err := extract.ProcessBody(fetchResult.Body, fetchResult.ContentType, baseURL, cfg)
+	if downloadOnly && errors.Is(err, stderrors.ErrUnsupportedContentType) {
+		return extract.Output{Metadata: map[string]any{}}, nil
+	}
+	return out, err
 }
@@ -0,0 +1,139 @@
+package prowler
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"testing"
+
+	"github.com/techbysteve/prowl4ai/internal/browser"
+	"github.com/techbysteve/prowl4ai/internal/cache"
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/model"
+)
+
+const testPage = `<html><head><title>Test</title></head><body><article><p>Hello from the test page, with enough words to keep.</p></article></body></html>`
+
+// fakeAdapter serves testPage with status and counts fetches.
+type fakeAdapter struct {
+	status  int
+	fetches atomic.Int32
+}
+
+func (a *fakeAdapter) Start(context.Context) error { return nil }
+func (a *fakeAdapter) Close(context.Context) error { return nil }
+
+func (a *fakeAdapter) FetchHTML(_ context.Context, _ string, _ config.CrawlerRunConfig) (browser.FetchResult, error) {
+	a.fetches.Add(1)
+	return browser.FetchResult{HTML: testPage, StatusCode: a.status, ContentType: "text/html"}, nil
+}
+
+func newTestService(t *testing.T, status int) (*Service, *fakeAdapter, cache.Store) {
+	t.Helper()
+	adapter := &fakeAdapter{status: status}
+	store, err := cache.OpenDisk(t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenDisk: %v", err)
+	}
+	s := NewService(adapter)
+	s.SetCache(store)
+	return s, adapter, store
+}
+
+func TestRunCacheModes(t *testing.T) {
+	const url = "https://example.com/page"
+	tests := []struct {
+		mode string
+		// want lists the cache status of two runs in a row.
+		want    [2]string
+		fetches int32
+		stored  int
+	}{
+		{config.CacheModeEnabled, [2]string{model.CacheStatusStored, model.CacheStatusHit}, 1, 1},
+		{config.CacheModeReadOnly, [2]string{model.CacheStatusMiss, model.CacheStatusMiss}, 2, 0},
+		{config.CacheModeWriteOnly, [2]string{model.CacheStatusStored, model.CacheStatusStored}, 2, 1},
+		{config.CacheModeBypass, [2]string{"", ""}, 2, 0},
+		{config.CacheModeDisabled, [2]string{"", ""}, 2, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			s, adapter, store := newTestService(t, 200)
+			cfg := config.DefaultCrawlerRunConfig()
+			cfg.CacheMode = tt.mode
+			for i, want := range tt.want {
+				result, err := s.Run(context.Background(), url, cfg)
+				if err != nil {
+					t.Fatalf("run %d: %v", i, err)
+				}
+				if result.CacheStatus != want {
+					t.Errorf("run %d: CacheStatus = %q, want %q", i, result.CacheStatus, want)
+				}
+			}
+			if got := adapter.fetches.Load(); got != tt.fetches {
+				t.Errorf("fetches = %d, want %d", got, tt.fetches)
+			}
+			if stats, _ := store.Stats(); stats.Entries != tt.stored {
+				t.Errorf("stored entries = %d, want %d", stats.Entries, tt.stored)
+			}
+		})
+	}
+}
+
+func TestRunReadOnlyServesEntries(t *testing.T) {
+	const url = "https://example.com/page"
+	s, adapter, _ := newTestService(t, 200)
+	cfg := config.DefaultCrawlerRunConfig()
+	cfg.CacheMode = config.CacheModeWriteOnly
+	if _, err := s.Run(context.Background(), url, cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.CacheMode = config.CacheModeReadOnly
+	result, err := s.Run(context.Background(), url, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.CacheStatus != model.CacheStatusHit || adapter.fetches.Load() != 1 {
+		t.Errorf("CacheStatus = %q after %d fetches, want a hit after 1", result.CacheStatus, adapter.fetches.Load())
+	}
+}
+
+func TestRunDoesNotCacheErrorResponses(t *testing.T) {
+	s, adapter, store := newTestService(t, 503)
+	cfg := config.DefaultCrawlerRunConfig()
+	cfg.CacheMode = config.CacheModeEnabled
+	for range 2 {
+		result, err := s.Run(context.Background(), "https://example.com/page", cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.CacheStatus != model.CacheStatusMiss {
+			t.Errorf("CacheStatus = %q, want miss", result.CacheStatus)
+		}
+	}
+	if adapter.fetches.Load() != 2 {
+		t.Errorf("fetches = %d, want 2", adapter.fetches.Load())
+	}
+	if stats, _ := store.Stats(); stats.Entries != 0 {
+		t.Errorf("stored %d entries for a 503", stats.Entries)
+	}
+}
+
+func TestRunReportsInvalidCacheSettings(t *testing.T) {
+	s, adapter, _ := newTestService(t, 200)
+	cfg := config.DefaultCrawlerRunConfig()
+
+	cfg.CacheMode = "sometimes"
+	if result, err := s.Run(context.Background(), "https://example.com/", cfg); err == nil || result.Success {
+		t.Errorf("unknown cache mode: Run = %+v, %v, want a failure", result, err)
+	}
+
+	cfg.CacheMode = config.CacheModeEnabled
+	cfg.Geolocation = &config.Geolocation{Latitude: math.NaN()}
+	result, err := s.Run(context.Background(), "https://example.com/", cfg)
+	if err == nil || result.Success || result.ErrorMessage == "" {
+		t.Errorf("unhashable settings: Run = %+v, %v, want a failed result", result, err)
+	}
+	if adapter.fetches.Load() != 0 {
+		t.Errorf("fetches = %d, want none", adapter.fetches.Load())
+	}
+}
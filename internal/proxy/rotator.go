@@ -0,0 +1,256 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+)
+
+// ErrNoHealthyProxy is returned when every proxy has been removed from rotation.
+var ErrNoHealthyProxy = errors.New("no healthy proxy available")
+
+type entry struct {
+	cfg      config.ProxyConfig
+	healthy  bool
+	failures int
+}
+
+// Rotator hands out proxies from a pool using a rotation strategy and drops
+// proxies from rotation after repeated failures or a failed health check.
+type Rotator struct {
+	strategy    string
+	maxFailures int
+	mu          sync.Mutex
+	entries     []*entry
+	next        int
+	sticky      map[string]*entry
+}
+
+// NewRotator builds a rotator over proxies. An empty strategy means round robin.
+func NewRotator(proxies []config.ProxyConfig, strategy string, maxFailures int) (*Rotator, error) {
+	switch strategy {
+	case "":
+		strategy = config.ProxyRotationRoundRobin
+	case config.ProxyRotationRoundRobin, config.ProxyRotationRandom, config.ProxyRotationSticky:
+	default:
+		return nil, fmt.Errorf("unsupported proxy rotation: %s", strategy)
+	}
+	entries := make([]*entry, 0, len(proxies))
+	for _, p := range proxies {
+		if p.Server == "" {
+			continue
+		}
+		entries = append(entries, &entry{cfg: p, healthy: true})
+	}
+	return &Rotator{
+		strategy:    strategy,
+		maxFailures: maxFailures,
+		entries:     entries,
+		sticky:      map[string]*entry{},
+	}, nil
+}
+
+// Len returns the number of proxies in the pool, healthy or not.
+func (r *Rotator) Len() int {
+	return len(r.entries)
+}
+
+// Pick selects the proxy for a request to targetURL.
+func (r *Rotator) Pick(targetURL string) (config.ProxyConfig, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	healthy := make([]*entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		if e.healthy {
+			healthy = append(healthy, e)
+		}
+	}
+	if len(healthy) == 0 {
+		return config.ProxyConfig{}, ErrNoHealthyProxy
+	}
+
+	switch r.strategy {
+	case config.ProxyRotationRandom:
+		return healthy[rand.IntN(len(healthy))].cfg, nil
+	case config.ProxyRotationSticky:
+		host := hostOf(targetURL)
+		if e, ok := r.sticky[host]; ok && e.healthy {
+			return e.cfg, nil
+		}
+		e := r.roundRobinLocked(healthy)
+		r.sticky[host] = e
+		return e.cfg, nil
+	default:
+		return r.roundRobinLocked(healthy).cfg, nil
+	}
+}
+
+func (r *Rotator) roundRobinLocked(healthy []*entry) *entry {
+	e := healthy[r.next%len(healthy)]
+	r.next++
+	return e
+}
+
+// ReportSuccess resets the failure count of the proxy with server.
+func (r *Rotator) ReportSuccess(server string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e := r.findLocked(server); e != nil {
+		e.failures = 0
+	}
+}
+
+// ReportFailure records a proxy-level failure and removes the proxy from
+// rotation once it fails maxFailures times in a row.
+func (r *Rotator) ReportFailure(server string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := r.findLocked(server)
+	if e == nil {
+		return
+	}
+	e.failures++
+	if r.maxFailures > 0 && e.failures >= r.maxFailures {
+		e.healthy = false
+	}
+}
+
+func (r *Rotator) findLocked(server string) *entry {
+	for _, e := range r.entries {
+		if e.cfg.Server == server {
+			return e
+		}
+	}
+	return nil
+}
+
+// CheckHealth requests checkURL through every proxy and keeps in rotation only
+// those that answer with a 2xx status. When a proxy has an IP configured, the
+// response body must contain it, so checkURL should echo the caller's address.
+func (r *Rotator) CheckHealth(ctx context.Context, checkURL string, timeout time.Duration) {
+	r.mu.Lock()
+	entries := append([]*entry{}, r.entries...)
+	r.mu.Unlock()
+
+	var wg sync.WaitGroup
+	results := make([]bool, len(entries))
+	for i, e := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = check(ctx, e.cfg, checkURL, timeout) == nil
+		}()
+	}
+	wg.Wait()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, e := range entries {
+		e.healthy = results[i]
+		if results[i] {
+			e.failures = 0
+		}
+	}
+}
+
+func check(ctx context.Context, p config.ProxyConfig, checkURL string, timeout time.Duration) error {
+	proxyURL, err := URL(p)
+	if err != nil {
+		return err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxyURL)
+	defer transport.CloseIdleConnections()
+	client := &http.Client{Transport: transport, Timeout: timeout}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checkURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("health check returned status %d", resp.StatusCode)
+	}
+	if p.IP == "" {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(body), p.IP) {
+		return fmt.Errorf("proxy %s exits from an address other than %s", p.Server, p.IP)
+	}
+	return nil
+}
+
+// URL returns the proxy server as a URL carrying its credentials.
+func URL(p config.ProxyConfig) (*url.URL, error) {
+	server := p.Server
+	if !strings.Contains(server, "://") {
+		server = "http://" + server
+	}
+	u, err := url.Parse(server)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy %q: %w", p.Server, err)
+	}
+	if p.Username != "" {
+		u.User = url.UserPassword(p.Username, p.Password)
+	}
+	return u, nil
+}
+
+// Parse reads a proxy given as "[scheme://][user:pass@]host:port".
+func Parse(value string) (config.ProxyConfig, error) {
+	raw := value
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return config.ProxyConfig{}, fmt.Errorf("invalid proxy %q", value)
+	}
+	p := config.ProxyConfig{Server: u.Scheme + "://" + u.Host}
+	if u.User != nil {
+		p.Username = u.User.Username()
+		p.Password, _ = u.User.Password()
+	}
+	return p, nil
+}
+
+// IsProxyError reports whether err looks like a failure of the proxy itself
+// rather than of the target site.
+func IsProxyError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	for _, marker := range []string{"ERR_PROXY", "ERR_TUNNEL_CONNECTION_FAILED", "NS_ERROR_PROXY", "proxyconnect"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return strings.ToLower(u.Hostname())
+}
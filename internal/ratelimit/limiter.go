@@ -0,0 +1,48 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HostLimiter spaces requests to the same host. It is safe for concurrent
+// use; concurrent callers for one host are scheduled one delay apart.
+type HostLimiter struct {
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+// NewHostLimiter returns an empty limiter.
+func NewHostLimiter() *HostLimiter {
+	return &HostLimiter{next: map[string]time.Time{}}
+}
+
+// Wait blocks until a request to host may start, at least delay after the
+// previous one, and reserves that slot. A non-positive delay returns at once.
+func (l *HostLimiter) Wait(ctx context.Context, host string, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	start := now
+	if next := l.next[host]; next.After(now) {
+		start = next
+	}
+	l.next[host] = start.Add(delay)
+	l.mu.Unlock()
+
+	wait := start.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,165 @@
+package robots
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTTL is how long a fetched robots.txt is reused.
+	DefaultTTL = 24 * time.Hour
+	// errorTTL is how long a host whose robots.txt could not be fetched
+	// stays fully disallowed before the fetch is retried.
+	errorTTL = time.Minute
+	// fetchTimeout bounds a single robots.txt request.
+	fetchTimeout = 10 * time.Second
+)
+
+// Verdict is the robots.txt decision for one URL.
+type Verdict struct {
+	Allowed bool
+	// Reason explains a refusal that no rule caused, such as an unreachable
+	// robots.txt.
+	Reason string
+	// CrawlDelay is the delay the site asks for between requests, or zero.
+	CrawlDelay time.Duration
+}
+
+// Checker fetches robots.txt once per host and answers for one user agent.
+// It is safe for concurrent use.
+type Checker struct {
+	userAgent string
+	client    *http.Client
+	ttl       time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*hostEntry
+}
+
+type hostEntry struct {
+	ready   chan struct{}
+	robots  *Robots
+	expires time.Time
+}
+
+// NewChecker returns a checker that evaluates rules for userAgent and sends
+// it when fetching robots.txt.
+func NewChecker(userAgent string) *Checker {
+	return &Checker{
+		userAgent: userAgent,
+		client:    &http.Client{Timeout: fetchTimeout},
+		ttl:       DefaultTTL,
+		hosts:     map[string]*hostEntry{},
+	}
+}
+
+// Check reports whether rawURL may be fetched and the host's Crawl-delay.
+func (c *Checker) Check(ctx context.Context, rawURL string) (Verdict, error) {
+	r, err := c.Robots(ctx, rawURL)
+	if err != nil {
+		return Verdict{}, err
+	}
+	return Verdict{
+		Allowed:    r.Allowed(c.userAgent, rawURL),
+		Reason:     r.unavailable,
+		CrawlDelay: r.CrawlDelay(c.userAgent),
+	}, nil
+}
+
+// Robots returns the parsed robots.txt for rawURL's host, fetching it when it
+// is not cached. Per RFC 9309, a missing file (4xx) allows everything, while
+// a server error or unreachable host disallows everything.
+func (c *Checker) Robots(ctx context.Context, rawURL string) (*Robots, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("robots: invalid url %q", rawURL)
+	}
+	origin := u.Scheme + "://" + u.Host
+
+	for {
+		c.mu.Lock()
+		entry, ok := c.hosts[origin]
+		if ok {
+			select {
+			case <-entry.ready:
+				if time.Now().After(entry.expires) {
+					ok = false
+				}
+			default:
+			}
+		}
+		if !ok {
+			entry = &hostEntry{ready: make(chan struct{})}
+			c.hosts[origin] = entry
+			c.mu.Unlock()
+			robots, expires := c.fetch(ctx, origin)
+			if err := ctx.Err(); err != nil {
+				// A cancelled fetch says nothing about the host. Waiters
+				// see a nil robots and retry with their own context.
+				c.mu.Lock()
+				if c.hosts[origin] == entry {
+					delete(c.hosts, origin)
+				}
+				c.mu.Unlock()
+				close(entry.ready)
+				return nil, err
+			}
+			entry.robots, entry.expires = robots, expires
+			close(entry.ready)
+			return entry.robots, nil
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-entry.ready:
+			if entry.robots != nil {
+				return entry.robots, nil
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// fetch downloads and parses origin's robots.txt and returns it with its
+// expiry time.
+func (c *Checker) fetch(ctx context.Context, origin string) (*Robots, time.Time) {
+	disallowAll := func(reason string) (*Robots, time.Time) {
+		r := &Robots{
+			groups:      []group{{agents: []string{"*"}, rules: []rule{{pattern: "/"}}}},
+			unavailable: reason,
+		}
+		return r, time.Now().Add(errorTTL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		return disallowAll(err.Error())
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return disallowAll("robots.txt unreachable: " + err.Error())
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize))
+		if err != nil {
+			return disallowAll("read robots.txt: " + err.Error())
+		}
+		return Parse(data), time.Now().Add(c.ttl)
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests:
+		return &Robots{}, time.Now().Add(c.ttl)
+	default:
+		return disallowAll(fmt.Sprintf("robots.txt returned status %d", resp.StatusCode))
+	}
+}
@@ -0,0 +1,88 @@
+package robots
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCheckerStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   bool
+	}{
+		{"missing file allows all", http.StatusNotFound, true},
+		{"forbidden file allows all", http.StatusForbidden, true},
+		{"rate limited disallows all", http.StatusTooManyRequests, false},
+		{"server error disallows all", http.StatusServiceUnavailable, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			verdict, err := NewChecker("TestBot").Check(context.Background(), srv.URL+"/page")
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if verdict.Allowed != tt.want {
+				t.Errorf("Allowed = %v, want %v", verdict.Allowed, tt.want)
+			}
+			if !tt.want && verdict.Reason == "" {
+				t.Error("Reason is empty for a disallowed host")
+			}
+		})
+	}
+}
+
+func TestCheckerCancelledFetchIsRetried(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+	}))
+	defer srv.Close()
+
+	c := NewChecker("TestBot")
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := c.Check(ctx, srv.URL+"/page")
+		first <- err
+	}()
+	<-started
+
+	// The second caller waits on the first one's fetch.
+	waiter := make(chan Verdict, 1)
+	go func() {
+		v, err := c.Check(context.Background(), srv.URL+"/page")
+		if err != nil {
+			t.Errorf("waiter Check: %v", err)
+		}
+		waiter <- v
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	if err := <-first; err == nil {
+		t.Fatal("cancelled Check returned no error")
+	}
+	close(release)
+	if v := <-waiter; !v.Allowed {
+		t.Errorf("waiter got a disallow verdict after the first caller was cancelled: %+v", v)
+	}
+}
@@ -0,0 +1,196 @@
+package robots
+
+import (
+	"bufio"
+	"bytes"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSize is the robots.txt prefix that is parsed; RFC 9309 requires at least
+// 500 KiB to be read and allows the rest to be ignored.
+const maxSize = 500 << 10
+
+// Robots is a parsed robots.txt file.
+type Robots struct {
+	groups []group
+	// Sitemaps lists the Sitemap URLs the file declares, in order.
+	Sitemaps []string
+	// unavailable explains why everything is disallowed when robots.txt
+	// could not be fetched.
+	unavailable string
+}
+
+type group struct {
+	agents     []string
+	rules      []rule
+	crawlDelay time.Duration
+}
+
+type rule struct {
+	allow   bool
+	pattern string
+}
+
+// Parse reads a robots.txt body. Unknown lines and malformed values are
+// ignored, as the format requires.
+func Parse(data []byte) *Robots {
+	if len(data) > maxSize {
+		data = data[:maxSize]
+	}
+	r := &Robots{}
+	var current *group
+	// A user-agent line after rules starts a new group; consecutive
+	// user-agent lines share one.
+	inAgents := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64<<10), maxSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				r.groups = append(r.groups, group{})
+				current = &r.groups[len(r.groups)-1]
+				inAgents = true
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+		case "allow", "disallow":
+			inAgents = false
+			if current == nil || value == "" {
+				// An empty Disallow allows everything, which is the default.
+				continue
+			}
+			current.rules = append(current.rules, rule{allow: key == "allow", pattern: value})
+		case "crawl-delay":
+			inAgents = false
+			if current == nil {
+				continue
+			}
+			if secs, err := strconv.ParseFloat(value, 64); err == nil && secs > 0 {
+				current.crawlDelay = time.Duration(secs * float64(time.Second))
+			}
+		case "sitemap":
+			if value != "" {
+				r.Sitemaps = append(r.Sitemaps, value)
+			}
+		}
+	}
+	return r
+}
+
+// Allowed reports whether userAgent may fetch rawURL. The longest matching
+// rule wins and Allow wins a tie; a URL no rule matches is allowed.
+func (r *Robots) Allowed(userAgent, rawURL string) bool {
+	path := requestPath(rawURL)
+	if path == "/robots.txt" {
+		return true
+	}
+	allowed, longest := true, -1
+	for _, g := range r.match(userAgent) {
+		for _, rl := range g.rules {
+			if !matchPattern(rl.pattern, path) {
+				continue
+			}
+			if n := len(rl.pattern); n > longest || (n == longest && rl.allow) {
+				allowed, longest = rl.allow, n
+			}
+		}
+	}
+	return allowed
+}
+
+// CrawlDelay returns the Crawl-delay that applies to userAgent, or zero.
+func (r *Robots) CrawlDelay(userAgent string) time.Duration {
+	var delay time.Duration
+	for _, g := range r.match(userAgent) {
+		delay = max(delay, g.crawlDelay)
+	}
+	return delay
+}
+
+// match returns the groups for userAgent: every group naming the longest
+// agent token found in userAgent, or else the "*" groups. Agent tokens
+// match case-insensitively anywhere in the user agent string, so a group
+// for "MyBot" applies to "Mozilla/5.0 (compatible; MyBot/1.0)".
+func (r *Robots) match(userAgent string) []group {
+	ua := strings.ToLower(userAgent)
+	best := ""
+	for _, g := range r.groups {
+		for _, agent := range g.agents {
+			if agent != "*" && agent != "" && len(agent) > len(best) && strings.Contains(ua, agent) {
+				best = agent
+			}
+		}
+	}
+	if best == "" {
+		best = "*"
+	}
+	var out []group
+	for _, g := range r.groups {
+		for _, agent := range g.agents {
+			if agent == best {
+				out = append(out, g)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// requestPath returns the escaped path and query of rawURL, as robots rules
+// are matched against it.
+func requestPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "/"
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return path
+}
+
+// matchPattern matches a rule path against path. "*" matches any run of
+// characters and a trailing "$" anchors the pattern at the end; otherwise
+// the pattern is a prefix match.
+func matchPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = pattern[:len(pattern)-1]
+	}
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	pos := len(parts[0])
+	for i, part := range parts[1:] {
+		if anchored && i == len(parts)-2 {
+			// The last part must end the path; take its final occurrence.
+			return len(path)-len(part) >= pos && strings.HasSuffix(path, part)
+		}
+		idx := strings.Index(path[pos:], part)
+		if idx < 0 {
+			return false
+		}
+		pos += idx + len(part)
+	}
+	return !anchored || pos == len(path)
+}
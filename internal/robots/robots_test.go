@@ -0,0 +1,69 @@
+package robots
+
+import "testing"
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"/", "/anything", true},
+		{"/private", "/private/page", true},
+		{"/private", "/public", false},
+		{"/*.php", "/index.php", true},
+		{"/*.php", "/dir/index.php?x=1", true},
+		{"/*.php", "/index.html", false},
+		{"/a*b*c", "/axxbyyc", true},
+		{"/a*b*c", "/axxcyyb", false},
+		{"/*.php$", "/index.php", true},
+		{"/*.php$", "/index.php?x=1", false},
+		{"/*.php$", "/a.php/b.php", true},
+		{"/exact$", "/exact", true},
+		{"/exact$", "/exact/more", false},
+		{"/*$", "/", true},
+		{"*", "/any", true},
+	}
+	for _, tt := range tests {
+		if got := matchPattern(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchPattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	robots := Parse([]byte(`
+User-agent: *
+Disallow: /private
+Allow: /private/public
+Disallow: /*.pdf$
+Allow: /tie
+Disallow: /tie
+Disallow: /search?
+
+User-agent: MyBot
+Disallow: /
+Allow: /bots
+`))
+	tests := []struct {
+		name, agent, url string
+		want             bool
+	}{
+		{"no rule matches", "Other", "https://example.com/page", true},
+		{"prefix disallow", "Other", "https://example.com/private/page", false},
+		{"longer allow wins", "Other", "https://example.com/private/public/page", true},
+		{"anchored wildcard", "Other", "https://example.com/docs/file.pdf", false},
+		{"anchored wildcard with query", "Other", "https://example.com/docs/file.pdf?v=1", true},
+		{"allow wins a tie", "Other", "https://example.com/tie", true},
+		{"query in path", "Other", "https://example.com/search?q=go", false},
+		{"robots.txt always allowed", "MyBot", "https://example.com/robots.txt", true},
+		{"named group replaces *", "Mozilla/5.0 (compatible; MyBot/1.0)", "https://example.com/page", false},
+		{"named group allow", "mybot", "https://example.com/bots/list", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := robots.Allowed(tt.agent, tt.url); got != tt.want {
+				t.Errorf("Allowed(%q, %q) = %v, want %v", tt.agent, tt.url, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/techbysteve/prowl4ai/internal/robots"
+)
+
+const (
+	// maxSize is the uncompressed size limit the sitemap protocol sets.
+	maxSize = 50 << 20
+	// DefaultMaxSitemaps bounds how many sitemaps one discovery fetches.
+	DefaultMaxSitemaps = 500
+	fetchTimeout       = 30 * time.Second
+)
+
+// commonPaths are probed when robots.txt declares no sitemaps.
+var commonPaths = []string{
+	"/sitemap.xml",
+	"/sitemap_index.xml",
+	"/sitemap-index.xml",
+	"/sitemap.xml.gz",
+	"/wp-sitemap.xml",
+}
+
+// Options controls sitemap discovery.
+type Options struct {
+	// Since and Until keep entries modified within the range. Entries
+	// without a lastmod (or news publication date) are dropped when either
+	// is set, and index children last modified before Since are skipped.
+	Since time.Time
+	Until time.Time
+	// MaxSitemaps bounds the sitemaps fetched, indexes included; zero uses
+	// DefaultMaxSitemaps.
+	MaxSitemaps int
+}
+
+// Result lists the entries found and the sitemaps they came from.
+type Result struct {
+	Entries  []Entry  `json:"entries"`
+	Sitemaps []string `json:"sitemaps"`
+	// Errors lists sitemaps that could not be fetched or parsed; discovery
+	// carries on without them.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Client fetches sitemaps over plain HTTP.
+type Client struct {
+	userAgent string
+	client    *http.Client
+}
+
+// NewClient returns a client that sends userAgent with its requests.
+func NewClient(userAgent string) *Client {
+	return &Client{userAgent: userAgent, client: &http.Client{Timeout: fetchTimeout}}
+}
+
+// Discover finds the sitemaps of site and returns their entries, deduplicated
+// by URL in the order they were listed. site is a host, a site URL, or the
+// URL of a sitemap to read directly. Sitemaps are taken from robots.txt, or
+// from common locations when it declares none.
+func (c *Client) Discover(ctx context.Context, site string, opts Options) (Result, error) {
+	siteURL, err := siteURL(site)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var roots []string
+	// guessed holds the common locations probed; their failures are expected.
+	guessed := map[string]bool{}
+	if isSitemapURL(siteURL) {
+		roots = []string{siteURL.String()}
+	} else {
+		roots, err = c.robotsSitemaps(ctx, siteURL)
+		if err != nil {
+			return Result{}, err
+		}
+		if len(roots) == 0 {
+			for _, path := range commonPaths {
+				guess := siteURL.Scheme + "://" + siteURL.Host + path
+				roots = append(roots, guess)
+				guessed[guess] = true
+			}
+		}
+	}
+
+	maxSitemaps := opts.MaxSitemaps
+	if maxSitemaps <= 0 {
+		maxSitemaps = DefaultMaxSitemaps
+	}
+	var (
+		result  Result
+		queue   = roots
+		visited = map[string]bool{}
+		seen    = map[string]bool{}
+	)
+	for len(queue) > 0 && len(result.Sitemaps) < maxSitemaps {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		sitemapURL := queue[0]
+		queue = queue[1:]
+		if visited[sitemapURL] {
+			continue
+		}
+		visited[sitemapURL] = true
+
+		doc, err := c.Fetch(ctx, sitemapURL)
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			if !guessed[sitemapURL] {
+				result.Errors = append(result.Errors, err.Error())
+			}
+			continue
+		}
+		result.Sitemaps = append(result.Sitemaps, sitemapURL)
+		for _, ref := range doc.Sitemaps {
+			if !opts.Since.IsZero() && !ref.LastMod.IsZero() && ref.LastMod.Before(opts.Since) {
+				continue
+			}
+			queue = append(queue, ref.URL)
+		}
+		for _, e := range doc.Entries {
+			if seen[e.URL] || !inRange(e.Modified(), opts) {
+				continue
+			}
+			seen[e.URL] = true
+			result.Entries = append(result.Entries, e)
+		}
+	}
+	if len(result.Sitemaps) == 0 {
+		if len(result.Errors) > 0 {
+			return result, fmt.Errorf("no readable sitemap for %s: %s", site, result.Errors[0])
+		}
+		return result, fmt.Errorf("no sitemap found for %s", site)
+	}
+	return result, nil
+}
+
+// Fetch downloads and parses one sitemap, decompressing gzip content.
+func (c *Client) Fetch(ctx context.Context, sitemapURL string) (Document, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sitemapURL, nil)
+	if err != nil {
+		return Document{}, fmt.Errorf("fetch sitemap %s: %w", sitemapURL, err)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Document{}, fmt.Errorf("fetch sitemap %s: %w", sitemapURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return Document{}, fmt.Errorf("fetch sitemap %s: status %d", sitemapURL, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize))
+	if err != nil {
+		return Document{}, fmt.Errorf("fetch sitemap %s: %w", sitemapURL, err)
+	}
+	// .xml.gz files are usually served as application/gzip rather than with
+	// a Content-Encoding the transport would undo, so sniff the magic bytes.
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return Document{}, fmt.Errorf("fetch sitemap %s: %w", sitemapURL, err)
+		}
+		data, err = io.ReadAll(io.LimitReader(zr, maxSize))
+		if err != nil {
+			return Document{}, fmt.Errorf("fetch sitemap %s: %w", sitemapURL, err)
+		}
+	}
+	return Parse(data, sitemapURL)
+}
+
+// robotsSitemaps returns the Sitemap URLs site's robots.txt declares.
+func (c *Client) robotsSitemaps(ctx context.Context, site *url.URL) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, site.Scheme+"://"+site.Host+"/robots.txt", nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// Fall back to the common locations.
+		return nil, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 500<<10))
+	if err != nil {
+		return nil, nil
+	}
+	return robots.Parse(data).Sitemaps, nil
+}
+
+func inRange(t time.Time, opts Options) bool {
+	if opts.Since.IsZero() && opts.Until.IsZero() {
+		return true
+	}
+	if t.IsZero() {
+		return false
+	}
+	return !t.Before(opts.Since) && (opts.Until.IsZero() || !t.After(opts.Until))
+}
+
+// siteURL parses site, defaulting to https when it has no scheme.
+func siteURL(site string) (*url.URL, error) {
+	site = strings.TrimSpace(site)
+	if !strings.Contains(site, "://") {
+		site = "https://" + site
+	}
+	u, err := url.Parse(site)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid site %q", site)
+	}
+	return u, nil
+}
+
+// isSitemapURL reports whether u points at a sitemap file rather than a site.
+func isSitemapURL(u *url.URL) bool {
+	path := strings.ToLower(u.Path)
+	return strings.HasSuffix(path, ".xml") || strings.HasSuffix(path, ".xml.gz") || strings.HasSuffix(path, ".txt")
+}
+
+// URLs returns the entries' page URLs, e.g. to seed a deep crawl.
+func URLs(entries []Entry) []string {
+	urls := make([]string, len(entries))
+	for i, e := range entries {
+		urls[i] = e.URL
+	}
+	return urls
+}
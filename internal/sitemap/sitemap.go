@@ -0,0 +1,219 @@
+package sitemap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// Entry is one page listed in a sitemap.
+type Entry struct {
+	URL        string    `json:"url"`
+	LastMod    time.Time `json:"lastmod,omitzero"`
+	ChangeFreq string    `json:"changefreq,omitempty"`
+	Priority   float64   `json:"priority,omitempty"`
+	// Sitemap is the sitemap the entry was listed in.
+	Sitemap string  `json:"sitemap"`
+	News    *News   `json:"news,omitempty"`
+	Images  []Image `json:"images,omitempty"`
+}
+
+// News holds the Google News sitemap extension fields.
+type News struct {
+	PublicationName     string    `json:"publication_name,omitempty"`
+	PublicationLanguage string    `json:"publication_language,omitempty"`
+	PublicationDate     time.Time `json:"publication_date,omitzero"`
+	Title               string    `json:"title,omitempty"`
+	Keywords            string    `json:"keywords,omitempty"`
+}
+
+// Image holds the Google image sitemap extension fields.
+type Image struct {
+	Loc     string `json:"loc"`
+	Title   string `json:"title,omitempty"`
+	Caption string `json:"caption,omitempty"`
+}
+
+// Modified returns the entry's last modification time, falling back to the
+// news publication date.
+func (e Entry) Modified() time.Time {
+	if e.LastMod.IsZero() && e.News != nil {
+		return e.News.PublicationDate
+	}
+	return e.LastMod
+}
+
+// Ref is a child sitemap listed in a sitemap index.
+type Ref struct {
+	URL     string
+	LastMod time.Time
+}
+
+// Document is a parsed sitemap: a urlset's entries or an index's children.
+type Document struct {
+	Entries  []Entry
+	Sitemaps []Ref
+}
+
+type xmlURLSet struct {
+	URLs []xmlURL `xml:"url"`
+}
+
+type xmlURL struct {
+	Loc        string     `xml:"loc"`
+	LastMod    string     `xml:"lastmod"`
+	ChangeFreq string     `xml:"changefreq"`
+	Priority   string     `xml:"priority"`
+	News       *xmlNews   `xml:"news"`
+	Images     []xmlImage `xml:"image"`
+}
+
+type xmlNews struct {
+	Publication struct {
+		Name     string `xml:"name"`
+		Language string `xml:"language"`
+	} `xml:"publication"`
+	PublicationDate string `xml:"publication_date"`
+	Title           string `xml:"title"`
+	Keywords        string `xml:"keywords"`
+}
+
+type xmlImage struct {
+	Loc     string `xml:"loc"`
+	Title   string `xml:"title"`
+	Caption string `xml:"caption"`
+}
+
+type xmlIndex struct {
+	Sitemaps []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"sitemap"`
+}
+
+// Parse reads an XML sitemap or sitemap index, or a plain-text sitemap with
+// one URL per line. source is recorded on the returned entries.
+func Parse(data []byte, source string) (Document, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] != '<' {
+		return parseText(trimmed, source), nil
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.CharsetReader = charset.NewReaderLabel
+	dec.Strict = false
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return Document{}, fmt.Errorf("parse sitemap %s: %w", source, err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "urlset":
+			var set xmlURLSet
+			if err := dec.DecodeElement(&set, &start); err != nil {
+				return Document{}, fmt.Errorf("parse sitemap %s: %w", source, err)
+			}
+			return Document{Entries: urlSetEntries(set, source)}, nil
+		case "sitemapindex":
+			var index xmlIndex
+			if err := dec.DecodeElement(&index, &start); err != nil {
+				return Document{}, fmt.Errorf("parse sitemap index %s: %w", source, err)
+			}
+			var doc Document
+			for _, s := range index.Sitemaps {
+				if loc := strings.TrimSpace(s.Loc); loc != "" {
+					doc.Sitemaps = append(doc.Sitemaps, Ref{URL: loc, LastMod: parseTime(s.LastMod)})
+				}
+			}
+			return doc, nil
+		default:
+			return Document{}, fmt.Errorf("parse sitemap %s: unexpected root element <%s>", source, start.Name.Local)
+		}
+	}
+}
+
+func urlSetEntries(set xmlURLSet, source string) []Entry {
+	entries := make([]Entry, 0, len(set.URLs))
+	for _, u := range set.URLs {
+		loc := strings.TrimSpace(u.Loc)
+		if loc == "" {
+			continue
+		}
+		e := Entry{
+			URL:        loc,
+			LastMod:    parseTime(u.LastMod),
+			ChangeFreq: strings.TrimSpace(u.ChangeFreq),
+			Sitemap:    source,
+		}
+		if p, err := strconv.ParseFloat(strings.TrimSpace(u.Priority), 64); err == nil {
+			e.Priority = p
+		}
+		if n := u.News; n != nil {
+			e.News = &News{
+				PublicationName:     strings.TrimSpace(n.Publication.Name),
+				PublicationLanguage: strings.TrimSpace(n.Publication.Language),
+				PublicationDate:     parseTime(n.PublicationDate),
+				Title:               strings.TrimSpace(n.Title),
+				Keywords:            strings.TrimSpace(n.Keywords),
+			}
+		}
+		for _, img := range u.Images {
+			if loc := strings.TrimSpace(img.Loc); loc != "" {
+				e.Images = append(e.Images, Image{
+					Loc:     loc,
+					Title:   strings.TrimSpace(img.Title),
+					Caption: strings.TrimSpace(img.Caption),
+				})
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func parseText(data []byte, source string) Document {
+	var doc Document
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "http://") || strings.HasPrefix(line, "https://") {
+			doc.Entries = append(doc.Entries, Entry{URL: line, Sitemap: source})
+		}
+	}
+	return doc
+}
+
+// timeLayouts are the W3C datetime forms sitemaps use, most specific first.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// parseTime parses a W3C datetime, returning the zero time when it is
+// missing or malformed.
+func parseTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
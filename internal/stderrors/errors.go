@@ -3,9 +3,21 @@ package stderrors
 import "errors"
 
 var (
-	ErrInvalidURL        = errors.New("invalid url")
-	ErrServiceNotReady   = errors.New("service not ready")
-	ErrBrowserNotStarted = errors.New("browser adapter not started")
-	ErrNavigationFailed  = errors.New("navigation failed")
-	ErrTimeout           = errors.New("operation timed out")
+	ErrInvalidURL             = errors.New("invalid url")
+	ErrServiceNotReady        = errors.New("service not ready")
+	ErrBrowserNotStarted      = errors.New("browser adapter not started")
+	ErrNavigationFailed       = errors.New("navigation failed")
+	ErrTimeout                = errors.New("operation timed out")
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+	ErrDownloadsDisabled      = errors.New("downloads are not accepted by browser config")
+	ErrBrowserCrashed         = errors.New("browser crashed")
+	ErrBrowserUnavailable     = errors.New("browser unavailable")
+	ErrNotCached              = errors.New("no cached version")
+	ErrRobotsDisallowed       = errors.New("disallowed by robots.txt")
 )
+
+// IsRetryable reports whether err is transient and the same request may
+// succeed if tried again.
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrBrowserCrashed) || errors.Is(err, ErrTimeout)
+}
@@ -0,0 +1,56 @@
+package urlutil
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Normalize returns a canonical form of rawURL so that equivalent addresses
+// compare equal: scheme and host are lowercased, default ports and fragments
+// are dropped, an empty path becomes "/" and query parameters are sorted.
+func Normalize(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("url is not absolute: %q", rawURL)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if port != "" {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	u.Host = host
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	if u.RawQuery != "" {
+		u.RawQuery = sortedQuery(u.RawQuery)
+	}
+	return u.String(), nil
+}
+
+// sortedQuery orders query parameters by key, keeping the relative order of
+// repeated keys.
+func sortedQuery(rawQuery string) string {
+	parts := strings.Split(rawQuery, "&")
+	sort.SliceStable(parts, func(i, j int) bool {
+		ki, _, _ := strings.Cut(parts[i], "=")
+		kj, _, _ := strings.Cut(parts[j], "=")
+		return ki < kj
+	})
+	return strings.Join(parts, "&")
+}
@@ -0,0 +1,247 @@
+package useragent
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+)
+
+// Browser and OS names accepted in generator filters.
+const (
+	BrowserChrome  = "chrome"
+	BrowserEdge    = "edge"
+	BrowserFirefox = "firefox"
+	BrowserSafari  = "safari"
+
+	OSWindows = "windows"
+	OSMacOS   = "macos"
+	OSLinux   = "linux"
+	OSAndroid = "android"
+	OSIOS     = "ios"
+)
+
+// Version ranges for generated user agents. Keep them close to current stable
+// releases so generated agents do not stand out.
+var (
+	chromiumMajors = []int{140, 141, 142, 143, 144}
+	firefoxMajors  = []int{141, 142, 143, 144, 145}
+	safariVersions = []string{"18.6", "26.0", "26.1"}
+)
+
+// ErrNoMatch is returned when the filters exclude every known profile.
+var ErrNoMatch = errors.New("no user agent profile matches the filters")
+
+// Options filters generated agents. Empty fields allow any value.
+type Options struct {
+	Browsers []string
+	OS       []string
+	// Platforms is "desktop" and/or "mobile".
+	Platforms []string
+}
+
+// Agent is a generated user agent and the client-hint headers that match it.
+type Agent struct {
+	UserAgent string            `json:"user_agent"`
+	Browser   string            `json:"browser"`
+	OS        string            `json:"os"`
+	Mobile    bool              `json:"mobile"`
+	Headers   map[string]string `json:"headers,omitempty"`
+}
+
+type profile struct {
+	browser string
+	os      string
+	mobile  bool
+	weight  int
+}
+
+// profiles approximates real-world traffic share per browser and OS.
+var profiles = []profile{
+	{BrowserChrome, OSWindows, false, 40},
+	{BrowserChrome, OSMacOS, false, 12},
+	{BrowserChrome, OSLinux, false, 4},
+	{BrowserChrome, OSAndroid, true, 20},
+	{BrowserEdge, OSWindows, false, 10},
+	{BrowserEdge, OSMacOS, false, 2},
+	{BrowserFirefox, OSWindows, false, 5},
+	{BrowserFirefox, OSMacOS, false, 2},
+	{BrowserFirefox, OSLinux, false, 2},
+	{BrowserFirefox, OSAndroid, true, 1},
+	{BrowserSafari, OSMacOS, false, 8},
+	{BrowserSafari, OSIOS, true, 15},
+}
+
+// OptionsFromMap reads filters from BrowserConfig.UserAgentGeneratorCfg,
+// which accepts "browsers", "os" and "platforms" as strings or string lists.
+func OptionsFromMap(m map[string]any) Options {
+	return Options{
+		Browsers:  stringList(m["browsers"]),
+		OS:        stringList(m["os"]),
+		Platforms: stringList(m["platforms"]),
+	}
+}
+
+func stringList(v any) []string {
+	switch t := v.(type) {
+	case string:
+		if t == "" {
+			return nil
+		}
+		return []string{strings.ToLower(t)}
+	case []string:
+		out := make([]string, 0, len(t))
+		for _, s := range t {
+			out = append(out, strings.ToLower(s))
+		}
+		return out
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				out = append(out, strings.ToLower(s))
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// BrowsersForEngine lists the browsers whose user agents are consistent with
+// a Playwright browser type, so the UA matches the engine's fingerprint.
+func BrowsersForEngine(browserType string) []string {
+	switch browserType {
+	case "firefox":
+		return []string{BrowserFirefox}
+	case "webkit":
+		return []string{BrowserSafari}
+	default:
+		return []string{BrowserChrome, BrowserEdge}
+	}
+}
+
+// Generate returns a random agent matching opts, weighted by traffic share.
+func Generate(opts Options) (Agent, error) {
+	var candidates []profile
+	total := 0
+	for _, p := range profiles {
+		if !allowed(opts.Browsers, p.browser) || !allowed(opts.OS, p.os) {
+			continue
+		}
+		platform := "desktop"
+		if p.mobile {
+			platform = "mobile"
+		}
+		if !allowed(opts.Platforms, platform) {
+			continue
+		}
+		candidates = append(candidates, p)
+		total += p.weight
+	}
+	if len(candidates) == 0 {
+		return Agent{}, fmt.Errorf("%w: %+v", ErrNoMatch, opts)
+	}
+
+	n := rand.IntN(total)
+	for _, p := range candidates {
+		if n < p.weight {
+			return build(p), nil
+		}
+		n -= p.weight
+	}
+	return build(candidates[len(candidates)-1]), nil
+}
+
+func allowed(filter []string, value string) bool {
+	return len(filter) == 0 || slices.Contains(filter, value)
+}
+
+func pick[T any](values []T) T {
+	return values[rand.IntN(len(values))]
+}
+
+func build(p profile) Agent {
+	agent := Agent{Browser: p.browser, OS: p.os, Mobile: p.mobile}
+	switch p.browser {
+	case BrowserChrome, BrowserEdge:
+		major := pick(chromiumMajors)
+		agent.UserAgent = chromiumUA(p, major)
+		agent.Headers = clientHints(p, major)
+	case BrowserFirefox:
+		agent.UserAgent = firefoxUA(p.os, pick(firefoxMajors))
+	case BrowserSafari:
+		agent.UserAgent = safariUA(p.os, pick(safariVersions))
+	}
+	return agent
+}
+
+// chromiumUA follows Chrome's reduced user-agent format, which freezes the
+// OS version and reports only the major browser version.
+func chromiumUA(p profile, major int) string {
+	var platform string
+	switch p.os {
+	case OSWindows:
+		platform = "Windows NT 10.0; Win64; x64"
+	case OSMacOS:
+		platform = "Macintosh; Intel Mac OS X 10_15_7"
+	case OSLinux:
+		platform = "X11; Linux x86_64"
+	case OSAndroid:
+		platform = "Linux; Android 10; K"
+	}
+	mobile := ""
+	if p.mobile {
+		mobile = "Mobile "
+	}
+	ua := fmt.Sprintf("Mozilla/5.0 (%s) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%d.0.0.0 %sSafari/537.36", platform, major, mobile)
+	if p.browser == BrowserEdge {
+		ua += fmt.Sprintf(" Edg/%d.0.0.0", major)
+	}
+	return ua
+}
+
+// clientHints returns the low-entropy Sec-CH-UA headers Chromium sends by
+// default, matching the generated user agent.
+func clientHints(p profile, major int) map[string]string {
+	brand := "Google Chrome"
+	if p.browser == BrowserEdge {
+		brand = "Microsoft Edge"
+	}
+	platform := map[string]string{
+		OSWindows: "Windows",
+		OSMacOS:   "macOS",
+		OSLinux:   "Linux",
+		OSAndroid: "Android",
+	}[p.os]
+	mobile := "?0"
+	if p.mobile {
+		mobile = "?1"
+	}
+	return map[string]string{
+		"sec-ch-ua":          fmt.Sprintf(`"%s";v="%d", "Chromium";v="%d", "Not_A Brand";v="24"`, brand, major, major),
+		"sec-ch-ua-mobile":   mobile,
+		"sec-ch-ua-platform": `"` + platform + `"`,
+	}
+}
+
+func firefoxUA(os string, major int) string {
+	switch os {
+	case OSWindows:
+		return fmt.Sprintf("Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:%d.0) Gecko/20100101 Firefox/%d.0", major, major)
+	case OSMacOS:
+		return fmt.Sprintf("Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:%d.0) Gecko/20100101 Firefox/%d.0", major, major)
+	case OSAndroid:
+		return fmt.Sprintf("Mozilla/5.0 (Android 14; Mobile; rv:%d.0) Gecko/%d.0 Firefox/%d.0", major, major, major)
+	default:
+		return fmt.Sprintf("Mozilla/5.0 (X11; Linux x86_64; rv:%d.0) Gecko/20100101 Firefox/%d.0", major, major)
+	}
+}
+
+func safariUA(os string, version string) string {
+	if os == OSIOS {
+		iosVersion := strings.ReplaceAll(version, ".", "_")
+		return fmt.Sprintf("Mozilla/5.0 (iPhone; CPU iPhone OS %s like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/%s Mobile/15E148 Safari/604.1", iosVersion, version)
+	}
+	return fmt.Sprintf("Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/%s Safari/605.1.15", version)
+}
@@ -2,16 +2,94 @@ package prowl4ai
 
 import (
 	"context"
+	"maps"
+	"slices"
 
 	"github.com/techbysteve/prowl4ai/internal/browser"
+	"github.com/techbysteve/prowl4ai/internal/cache"
 	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/extract"
 	"github.com/techbysteve/prowl4ai/internal/model"
 	"github.com/techbysteve/prowl4ai/internal/prowler"
+	"github.com/techbysteve/prowl4ai/internal/robots"
+	"github.com/techbysteve/prowl4ai/internal/stderrors"
 )
 
 // CrawlResult is the structured output returned by a crawl run.
 type CrawlResult = model.CrawlResult
 
+// PoolStats reports browser page pool usage and queue-wait metrics.
+type PoolStats = browser.PoolStats
+
+// ProxyConfig describes one proxy server. IP, when set, is the exit address
+// proxy health checks expect to see.
+type ProxyConfig = config.ProxyConfig
+
+// Geolocation is the position reported to pages during emulation.
+type Geolocation = config.Geolocation
+
+// Download describes a file saved during a crawl.
+type Download = model.Download
+
+// ExtractionSchema describes structured data to scrape from a page: one
+// object per element matching BaseSelector, built from Fields with CSS or
+// XPath selectors.
+type ExtractionSchema = config.ExtractionSchema
+
+// SchemaField is one field of an ExtractionSchema.
+type SchemaField = config.SchemaField
+
+// Entity is a distinct pattern match with the character offsets of each
+// occurrence in CrawlResult.Text.
+type Entity = model.Entity
+
+// ContentFilterSpec declares one content filter of RunConfig.ContentFilters.
+type ContentFilterSpec = config.ContentFilterConfig
+
+// ContentFilter keeps the parts of a cleaned HTML page worth converting to
+// the fit Markdown and returns them as HTML. Implement it to add custom
+// filters to RunConfig.CustomContentFilters.
+type ContentFilter = extract.ContentFilter
+
+// Built-in content filters, for composing with custom ones.
+type (
+	PruningFilter = extract.PruningFilter
+	BM25Filter    = extract.BM25Filter
+)
+
+// Table is a data table with headers and span-resolved rows.
+type Table = model.Table
+
+// HreflangLink is a translation of a page; crawl results list them in
+// Metadata["hreflang"].
+type HreflangLink = extract.HreflangLink
+
+// StructuredItem is a microdata or RDFa item from Metadata["microdata"] or
+// Metadata["rdfa"].
+type StructuredItem = extract.StructuredItem
+
+// ErrBrowserCrashed is returned when the browser dies during a crawl. The
+// browser is relaunched on the next call, so the crawl can be retried.
+var ErrBrowserCrashed = stderrors.ErrBrowserCrashed
+
+// ErrBrowserUnavailable is returned when the browser cannot be relaunched,
+// either because launches keep failing or because MaxBrowserRestarts is spent.
+// It is not retryable.
+var ErrBrowserUnavailable = stderrors.ErrBrowserUnavailable
+
+// ErrRobotsDisallowed is returned when robots.txt forbids fetching a URL.
+// The result's ErrorCode is ErrorCodeRobotsDisallowed.
+var ErrRobotsDisallowed = stderrors.ErrRobotsDisallowed
+
+// ErrorCodeRobotsDisallowed marks results for URLs robots.txt forbids.
+const ErrorCodeRobotsDisallowed = model.ErrorCodeRobotsDisallowed
+
+// IsRetryable reports whether a crawl error is transient, such as a browser
+// crash or timeout, and the same URL may succeed on another attempt.
+func IsRetryable(err error) bool {
+	return stderrors.IsRetryable(err)
+}
+
 // BrowserConfig controls browser startup behavior for library users.
 type BrowserConfig struct {
 	BrowserType    string
@@ -21,8 +99,54 @@ type BrowserConfig struct {
 	ViewportWidth  int
 	ViewportHeight int
 	UserAgent      string
-	ExtraArgs      []string
-	DebuggingPort  int
+	// UserAgentMode "random" generates a realistic user agent with matching
+	// client hints per context instead of UserAgent; the hints are sent with
+	// page navigations only. UserAgentGeneratorCfg narrows the choice with
+	// "browsers", "os" and "platforms" filters, though browser contexts
+	// always get desktop agents to match their viewport.
+	UserAgentMode         string
+	UserAgentGeneratorCfg map[string]any
+	ExtraArgs             []string
+	DebuggingPort         int
+	// Headers are sent with every request. Cookies are loaded into every
+	// context before its first page, in Playwright's shape: "name", "value"
+	// and either "url" or "domain" with an optional "path".
+	Headers map[string]string
+	Cookies []map[string]any
+	// FetchMode is "browser" (Playwright), "http" (net/http only) or "auto"
+	// (HTTP first, Playwright when the page looks JavaScript-rendered).
+	// MaxResponseBytes caps the decompressed body an HTTP fetch reads;
+	// larger responses fail the crawl.
+	FetchMode        string
+	MaxResponseBytes int64
+	// MaxConcurrentPages caps open pages per browser and MaxPagesPerContext
+	// caps pages sharing one browser context. Pages with the same emulation
+	// and proxy share a context, including its cookies and storage; idle
+	// contexts are kept for reuse for up to a minute, a few per browser, and
+	// closed when the browser is recycled or closed. RecycleAfterPages
+	// relaunches the browser after that many pages; zero disables recycling.
+	MaxConcurrentPages int
+	MaxPagesPerContext int
+	RecycleAfterPages  int
+	// MaxBrowserRestarts bounds browser launches after crashes to that many
+	// in any ten minutes; once spent, crawls fail with ErrBrowserUnavailable
+	// until the window frees up. Zero or less removes the bound.
+	MaxBrowserRestarts int
+	// Proxies is a rotation pool applied per request through per-context
+	// proxies. ProxyRotation is "round_robin", "random" or "sticky" (one proxy
+	// per target host). Proxies failing ProxyMaxFailures requests in a row, or
+	// the ProxyHealthCheckURL check at start, are removed from rotation.
+	Proxies             []ProxyConfig
+	ProxyRotation       string
+	ProxyHealthCheckURL string
+	ProxyMaxFailures    int
+	// AcceptDownloads saves files the page downloads into DownloadsPath.
+	// MaxDownloadBytes caps each file: a download whose Content-Length is
+	// larger is cancelled as it starts, and one sent without a length is
+	// removed once saved if it is over the cap.
+	AcceptDownloads  bool
+	DownloadsPath    string
+	MaxDownloadBytes int64
 }
 
 // RunConfig controls a single crawl execution.
@@ -33,45 +157,106 @@ type RunConfig struct {
 	WaitForTimeoutMs int
 	EnableCleanHTML  bool
 	EnableMarkdown   bool
-	OnlyText         bool
-	CSSSelector      string
-	Verbose          bool
+	// EnableLinks fills CrawlResult.Links with the page's internal and
+	// external links. Deep crawls always enable it.
+	EnableLinks bool
+	OnlyText    bool
+	CSSSelector string
+	Verbose     bool
+	// DownloadSelector is clicked after load to trigger a download.
+	DownloadSelector  string
+	DownloadTimeoutMs int
+	// Device names a Playwright device profile (e.g. "iPhone 13") whose user
+	// agent, viewport, scale factor, touch and mobile settings are emulated.
+	// Locale, TimezoneID, Geolocation and ColorScheme override the context
+	// defaults; runs with different settings get separate browser contexts,
+	// and only a few idle ones are kept, so varying them per run does not
+	// accumulate contexts.
+	Device      string
+	Locale      string
+	TimezoneID  string
+	Geolocation *Geolocation
+	ColorScheme string
+	// ProxyConfig routes this run through a specific proxy, bypassing rotation.
+	ProxyConfig *ProxyConfig
+	// Headers are sent with the page request only, not its subresources.
+	Headers map[string]string
+	// CacheMode is "enabled", "disabled", "read_only", "write_only" or
+	// "bypass" (the default). Cached entries keep the raw response, so runs
+	// with different extraction settings are served without refetching.
+	// Entries older than CacheMaxAgeMs are revalidated with If-None-Match and
+	// If-Modified-Since; a 304 serves the cached result as "not_modified".
+	CacheMode     string
+	CacheMaxAgeMs int
+	// CheckRobotsTxt fetches each host's robots.txt once, refuses URLs it
+	// disallows for the crawler's user agent and waits out its Crawl-delay
+	// between requests to the host. Deep crawls check unless told not to.
+	CheckRobotsTxt bool
+	// ExtractionSchema scrapes repeated items, such as product cards or job
+	// postings, into CrawlResult.ExtractedContent.
+	ExtractionSchema *ExtractionSchema
+	// EntityTypes enables built-in entity patterns in CrawlResult.Entities:
+	// "email", "phone", "url", "ipv4", "ipv6", "date", "price",
+	// "postal_code", "uuid", or "all". EntityPatterns adds named regexes.
+	EntityTypes    []string
+	EntityPatterns map[string]string
+	// EnableTables fills CrawlResult.Tables with the page's data tables;
+	// Table.WriteCSV exports one.
+	EnableTables bool
+	// ContentFilters fill CrawlResult.FitMarkdown with the blocks of the
+	// cleaned page they keep, applied in order: "pruning" drops low-value
+	// blocks such as sidebars, "bm25" keeps the blocks relevant to a query.
+	ContentFilters []ContentFilterSpec
+	// CustomContentFilters run after ContentFilters, each on the previous
+	// output. Cached pages are re-extracted for runs that set them.
+	CustomContentFilters []ContentFilter
+	// EnableCitations writes links in Markdown and FitMarkdown as numbered
+	// references, "text [1]", listed with their URL and title in a
+	// References section at the end.
+	EnableCitations bool
 }
 
 // DefaultBrowserConfig returns sensible browser defaults.
 func DefaultBrowserConfig() BrowserConfig {
 	cfg := config.DefaultBrowserConfig()
 	return BrowserConfig{
-		BrowserType:    cfg.BrowserType,
-		Headless:       cfg.Headless,
-		Channel:        cfg.Channel,
-		Proxy:          cfg.Proxy,
-		ViewportWidth:  cfg.ViewportWidth,
-		ViewportHeight: cfg.ViewportHeight,
-		UserAgent:      cfg.UserAgent,
-		ExtraArgs:      append([]string{}, cfg.ExtraArgs...),
-		DebuggingPort:  cfg.DebuggingPort,
+		BrowserType:           cfg.BrowserType,
+		Headless:              cfg.Headless,
+		Channel:               cfg.Channel,
+		Proxy:                 cfg.Proxy,
+		ViewportWidth:         cfg.ViewportWidth,
+		ViewportHeight:        cfg.ViewportHeight,
+		UserAgent:             cfg.UserAgent,
+		UserAgentMode:         cfg.UserAgentMode,
+		UserAgentGeneratorCfg: maps.Clone(cfg.UserAgentGeneratorCfg),
+		ExtraArgs:             append([]string{}, cfg.ExtraArgs...),
+		DebuggingPort:         cfg.DebuggingPort,
+		Headers:               maps.Clone(cfg.Headers),
+		Cookies:               cloneCookies(cfg.Cookies),
+		FetchMode:             cfg.FetchMode,
+		MaxResponseBytes:      cfg.MaxResponseBytes,
+		MaxConcurrentPages:    cfg.MaxConcurrentPages,
+		MaxPagesPerContext:    cfg.MaxPagesPerContext,
+		RecycleAfterPages:     cfg.RecycleAfterPages,
+		MaxBrowserRestarts:    cfg.MaxBrowserRestarts,
+		Proxies:               append([]ProxyConfig{}, cfg.Proxies...),
+		ProxyRotation:         cfg.ProxyRotation,
+		ProxyHealthCheckURL:   cfg.ProxyHealthCheckURL,
+		ProxyMaxFailures:      cfg.ProxyMaxFailures,
+		AcceptDownloads:       cfg.AcceptDownloads,
+		DownloadsPath:         cfg.DownloadsPath,
+		MaxDownloadBytes:      cfg.MaxDownloadBytes,
 	}
 }
 
 // DefaultRunConfig returns sensible crawl defaults.
 func DefaultRunConfig() RunConfig {
-	cfg := config.DefaultCrawlerRunConfig()
-	return RunConfig{
-		PageTimeoutMs:    cfg.PageTimeoutMs,
-		WaitUntil:        cfg.WaitUntil,
-		WaitFor:          cfg.WaitFor,
-		WaitForTimeoutMs: cfg.WaitForTimeoutMs,
-		EnableCleanHTML:  cfg.EnableCleanHTML,
-		EnableMarkdown:   cfg.EnableMarkdown,
-		OnlyText:         cfg.OnlyText,
-		CSSSelector:      cfg.CSSSelector,
-		Verbose:          cfg.Verbose,
-	}
+	return fromInternalRunConfig(config.DefaultCrawlerRunConfig())
 }
 
 // Crawler is the main Go library entrypoint.
 type Crawler struct {
+	adapter          browser.Adapter
 	service          *prowler.Service
 	defaultRunConfig config.CrawlerRunConfig
 }
@@ -84,19 +269,51 @@ func NewCrawler() *Crawler {
 // NewCrawlerWithConfig builds a crawler with explicit browser and run defaults.
 func NewCrawlerWithConfig(browserCfg BrowserConfig, runCfg RunConfig) *Crawler {
 	internalBrowserCfg := toInternalBrowserConfig(browserCfg)
-	adapter := browser.NewPlaywrightAdapter(internalBrowserCfg)
+	adapter := browser.NewAdapter(internalBrowserCfg)
 	service := prowler.NewService(adapter)
+	service.SetRobots(robots.NewChecker(internalBrowserCfg.UserAgent))
 	return &Crawler{
+		adapter:          adapter,
 		service:          service,
 		defaultRunConfig: toInternalRunConfig(runCfg),
 	}
 }
 
+// NewCrawlerWithAdapter builds a crawler that fetches pages through adapter
+// instead of a built-in backend, e.g. a remote rendering service or a test double.
+// A nil adapter falls back to the default browser configuration, as NewCrawler.
+func NewCrawlerWithAdapter(adapter Adapter, runCfg RunConfig) *Crawler {
+	var internalAdapter browser.Adapter
+	switch a := adapter.(type) {
+	case nil:
+		return NewCrawlerWithConfig(DefaultBrowserConfig(), runCfg)
+	case builtinAdapter:
+		internalAdapter = a.adapter
+	default:
+		internalAdapter = adapterBridge{adapter: a}
+	}
+	return &Crawler{
+		adapter:          internalAdapter,
+		service:          prowler.NewService(internalAdapter),
+		defaultRunConfig: toInternalRunConfig(runCfg),
+	}
+}
+
 // SetDefaultRunConfig updates the default run config used by Crawl.
 func (c *Crawler) SetDefaultRunConfig(cfg RunConfig) {
 	c.defaultRunConfig = toInternalRunConfig(cfg)
 }
 
+// SetCacheDir stores cached crawls in dir instead of the user cache directory.
+func (c *Crawler) SetCacheDir(dir string) error {
+	store, err := cache.OpenDisk(dir)
+	if err != nil {
+		return err
+	}
+	c.service.SetCache(store)
+	return nil
+}
+
 // Crawl executes one crawl using the crawler's default run config.
 func (c *Crawler) Crawl(ctx context.Context, url string) (CrawlResult, error) {
 	return c.service.Run(ctx, url, c.defaultRunConfig)
@@ -107,6 +324,16 @@ func (c *Crawler) CrawlWithConfig(ctx context.Context, url string, cfg RunConfig
 	return c.service.Run(ctx, url, toInternalRunConfig(cfg))
 }
 
+// PoolStats reports page pool metrics. The second result is false when the
+// crawler's adapter does not use a page pool.
+func (c *Crawler) PoolStats() (PoolStats, bool) {
+	pooled, ok := c.adapter.(browser.PoolStatser)
+	if !ok {
+		return PoolStats{}, false
+	}
+	return pooled.PoolStats(), true
+}
+
 // Close releases browser resources.
 func (c *Crawler) Close(ctx context.Context) error {
 	return c.service.Close(ctx)
@@ -121,11 +348,74 @@ func toInternalBrowserConfig(cfg BrowserConfig) config.BrowserConfig {
 	base.ViewportWidth = cfg.ViewportWidth
 	base.ViewportHeight = cfg.ViewportHeight
 	base.UserAgent = cfg.UserAgent
+	base.UserAgentMode = cfg.UserAgentMode
+	base.UserAgentGeneratorCfg = maps.Clone(cfg.UserAgentGeneratorCfg)
 	base.ExtraArgs = append([]string{}, cfg.ExtraArgs...)
 	base.DebuggingPort = cfg.DebuggingPort
+	if cfg.Headers != nil {
+		base.Headers = maps.Clone(cfg.Headers)
+	}
+	if cfg.Cookies != nil {
+		base.Cookies = cloneCookies(cfg.Cookies)
+	}
+	base.FetchMode = cfg.FetchMode
+	base.MaxResponseBytes = cfg.MaxResponseBytes
+	base.MaxConcurrentPages = cfg.MaxConcurrentPages
+	base.MaxPagesPerContext = cfg.MaxPagesPerContext
+	base.RecycleAfterPages = cfg.RecycleAfterPages
+	base.MaxBrowserRestarts = cfg.MaxBrowserRestarts
+	base.Proxies = append([]config.ProxyConfig{}, cfg.Proxies...)
+	base.ProxyRotation = cfg.ProxyRotation
+	base.ProxyHealthCheckURL = cfg.ProxyHealthCheckURL
+	base.ProxyMaxFailures = cfg.ProxyMaxFailures
+	base.AcceptDownloads = cfg.AcceptDownloads
+	base.DownloadsPath = cfg.DownloadsPath
+	base.MaxDownloadBytes = cfg.MaxDownloadBytes
 	return base
 }
 
+func cloneCookies(cookies []map[string]any) []map[string]any {
+	out := make([]map[string]any, len(cookies))
+	for i, c := range cookies {
+		out[i] = maps.Clone(c)
+	}
+	return out
+}
+
+func fromInternalRunConfig(cfg config.CrawlerRunConfig) RunConfig {
+	return RunConfig{
+		PageTimeoutMs:        cfg.PageTimeoutMs,
+		WaitUntil:            cfg.WaitUntil,
+		WaitFor:              cfg.WaitFor,
+		WaitForTimeoutMs:     cfg.WaitForTimeoutMs,
+		EnableCleanHTML:      cfg.EnableCleanHTML,
+		EnableMarkdown:       cfg.EnableMarkdown,
+		EnableLinks:          cfg.EnableLinks,
+		OnlyText:             cfg.OnlyText,
+		CSSSelector:          cfg.CSSSelector,
+		Verbose:              cfg.Verbose,
+		DownloadSelector:     cfg.DownloadSelector,
+		DownloadTimeoutMs:    cfg.DownloadTimeoutMs,
+		Device:               cfg.Device,
+		Locale:               cfg.Locale,
+		TimezoneID:           cfg.TimezoneID,
+		Geolocation:          cfg.Geolocation,
+		ColorScheme:          cfg.ColorScheme,
+		ProxyConfig:          cfg.ProxyConfig,
+		Headers:              maps.Clone(cfg.Headers),
+		CacheMode:            cfg.CacheMode,
+		CacheMaxAgeMs:        cfg.CacheMaxAgeMs,
+		CheckRobotsTxt:       cfg.CheckRobotsTxt,
+		ExtractionSchema:     cfg.ExtractionSchema,
+		EntityTypes:          slices.Clone(cfg.EntityTypes),
+		EntityPatterns:       maps.Clone(cfg.EntityPatterns),
+		EnableTables:         cfg.EnableTables,
+		ContentFilters:       slices.Clone(cfg.ContentFilters),
+		EnableCitations:      cfg.EnableCitations,
+		CustomContentFilters: slices.Clone(cfg.CustomContentFilters),
+	}
+}
+
 func toInternalRunConfig(cfg RunConfig) config.CrawlerRunConfig {
 	base := config.DefaultCrawlerRunConfig()
 	base.PageTimeoutMs = cfg.PageTimeoutMs
@@ -134,8 +424,28 @@ func toInternalRunConfig(cfg RunConfig) config.CrawlerRunConfig {
 	base.WaitForTimeoutMs = cfg.WaitForTimeoutMs
 	base.EnableCleanHTML = cfg.EnableCleanHTML
 	base.EnableMarkdown = cfg.EnableMarkdown
+	base.EnableLinks = cfg.EnableLinks
 	base.OnlyText = cfg.OnlyText
 	base.CSSSelector = cfg.CSSSelector
 	base.Verbose = cfg.Verbose
+	base.DownloadSelector = cfg.DownloadSelector
+	base.DownloadTimeoutMs = cfg.DownloadTimeoutMs
+	base.Device = cfg.Device
+	base.Locale = cfg.Locale
+	base.TimezoneID = cfg.TimezoneID
+	base.Geolocation = cfg.Geolocation
+	base.ColorScheme = cfg.ColorScheme
+	base.ProxyConfig = cfg.ProxyConfig
+	base.Headers = maps.Clone(cfg.Headers)
+	base.CacheMode = cfg.CacheMode
+	base.CacheMaxAgeMs = cfg.CacheMaxAgeMs
+	base.CheckRobotsTxt = cfg.CheckRobotsTxt
+	base.ExtractionSchema = cfg.ExtractionSchema
+	base.EntityTypes = slices.Clone(cfg.EntityTypes)
+	base.EntityPatterns = maps.Clone(cfg.EntityPatterns)
+	base.EnableTables = cfg.EnableTables
+	base.ContentFilters = slices.Clone(cfg.ContentFilters)
+	base.EnableCitations = cfg.EnableCitations
+	base.CustomContentFilters = slices.Clone(cfg.CustomContentFilters)
 	return base
 }
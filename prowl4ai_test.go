@@ -0,0 +1,102 @@
+package prowl4ai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type fixtureAdapter struct {
+	started, closed bool
+	urls            []string
+}
+
+func (a *fixtureAdapter) Start(context.Context) error {
+	a.started = true
+	return nil
+}
+
+func (a *fixtureAdapter) Close(context.Context) error {
+	a.closed = true
+	return nil
+}
+
+func (a *fixtureAdapter) FetchHTML(_ context.Context, url string, _ RunConfig) (FetchResult, error) {
+	a.urls = append(a.urls, url)
+	return FetchResult{
+		HTML:          "<html><head><title>Fixture</title></head><body><article><h1>Fixture</h1><p>Hello from the fixture adapter.</p></article></body></html>",
+		StatusCode:    200,
+		ContentType:   "text/html",
+		RedirectedURL: url,
+	}, nil
+}
+
+func TestCrawlerWithAdapter(t *testing.T) {
+	adapter := &fixtureAdapter{}
+	crawler := NewCrawlerWithAdapter(adapter, DefaultRunConfig())
+
+	result, err := crawler.Crawl(context.Background(), "https://example.com/page")
+	if err != nil {
+		t.Fatalf("Crawl: %v", err)
+	}
+	if !adapter.started {
+		t.Error("Crawl did not start the adapter")
+	}
+	if len(adapter.urls) != 1 || adapter.urls[0] != "https://example.com/page" {
+		t.Errorf("adapter fetched %q, want the crawled URL once", adapter.urls)
+	}
+	if result.StatusCode != 200 || !strings.Contains(string(result.Markdown), "Hello from the fixture adapter.") {
+		t.Errorf("result = status %d, markdown %q", result.StatusCode, result.Markdown)
+	}
+	if _, ok := crawler.PoolStats(); ok {
+		t.Error("PoolStats reports a page pool for a custom adapter")
+	}
+
+	if err := crawler.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if !adapter.closed {
+		t.Error("Close did not close the adapter")
+	}
+}
+
+func TestNewCrawlerWithNilAdapter(t *testing.T) {
+	crawler := NewCrawlerWithAdapter(nil, DefaultRunConfig())
+	if crawler.adapter == nil {
+		t.Fatal("a nil adapter left the crawler without one")
+	}
+}
+
+func TestBrowserConfigHeadersAndCookies(t *testing.T) {
+	var gotHeader, gotCookie string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Test")
+		if c, err := r.Cookie("session"); err == nil {
+			gotCookie = c.Value
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html><body><p>ok</p></body></html>"))
+	}))
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultBrowserConfig()
+	cfg.FetchMode = "http"
+	cfg.Headers = map[string]string{"X-Test": "yes"}
+	cfg.Cookies = []map[string]any{{"name": "session", "value": "abc", "domain": u.Hostname()}}
+	crawler := NewCrawlerWithConfig(cfg, DefaultRunConfig())
+	defer crawler.Close(context.Background())
+
+	if _, err := crawler.Crawl(context.Background(), server.URL); err != nil {
+		t.Fatalf("Crawl: %v", err)
+	}
+	if gotHeader != "yes" || gotCookie != "abc" {
+		t.Errorf("server saw header %q and cookie %q, want %q and %q", gotHeader, gotCookie, "yes", "abc")
+	}
+}
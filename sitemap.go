@@ -0,0 +1,38 @@
+package prowl4ai
+
+import (
+	"context"
+
+	"github.com/techbysteve/prowl4ai/internal/config"
+	"github.com/techbysteve/prowl4ai/internal/sitemap"
+)
+
+// SitemapEntry is a page listed in a sitemap, with its lastmod, change
+// frequency, priority and any news or image extension data.
+type SitemapEntry = sitemap.Entry
+
+// Sitemap extension data attached to entries.
+type (
+	SitemapNews  = sitemap.News
+	SitemapImage = sitemap.Image
+)
+
+// SitemapOptions filters entries by lastmod and bounds the sitemaps fetched.
+type SitemapOptions = sitemap.Options
+
+// SitemapResult lists the entries found, the sitemaps read and the sitemaps
+// that failed.
+type SitemapResult = sitemap.Result
+
+// DiscoverSitemaps finds site's sitemaps through robots.txt, or common
+// locations when it declares none, and returns their entries. Indexes are
+// followed and gzip sitemaps decompressed. site may also be the URL of a
+// sitemap to read directly.
+func DiscoverSitemaps(ctx context.Context, site string, opts SitemapOptions) (SitemapResult, error) {
+	return sitemap.NewClient(config.DefaultUserAgent).Discover(ctx, site, opts)
+}
+
+// SitemapURLs returns the entries' page URLs, e.g. as deep crawl seeds.
+func SitemapURLs(entries []SitemapEntry) []string {
+	return sitemap.URLs(entries)
+}